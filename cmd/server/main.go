@@ -7,6 +7,7 @@ import (
 	"github.com/auth-service/internal/config"
 	"github.com/auth-service/internal/database"
 	"github.com/auth-service/internal/handlers"
+	"github.com/auth-service/internal/jobs"
 	"github.com/auth-service/internal/middleware"
 	"github.com/auth-service/internal/repository"
 	"github.com/auth-service/internal/services"
@@ -37,15 +38,58 @@ func main() {
 	tokenRepo := repository.NewTokenRepository(db)
 	roleRepo := repository.NewRoleRepository(db)
 	auditRepo := repository.NewAuditRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	consentRepo := repository.NewConsentRepository(db)
+	deviceRepo := repository.NewDeviceRepository(db)
+	permissionRepo := repository.NewPermissionRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
+	groupRepo := repository.NewGroupRepository(db)
+	relationRepo := repository.NewRelationRepository(db)
+	elevationRepo := repository.NewElevationRepository(db)
+
+	metadataValidator, err := services.NewMetadataValidator(cfg.UserMetadataSchemaFile)
+	if err != nil {
+		log.Fatalf("Failed to load user metadata schema: %v", err)
+	}
+
+	policyEngine, err := services.NewPolicyEngine(cfg.PolicyFile)
+	if err != nil {
+		log.Fatalf("Failed to load access policies: %v", err)
+	}
+
+	relationNamespaces, err := services.LoadRelationNamespaces(cfg.RelationNamespaceFile)
+	if err != nil {
+		log.Fatalf("Failed to load relation namespaces: %v", err)
+	}
+
+	geoService, err := services.NewGeoService(cfg, auditRepo)
+	if err != nil {
+		log.Fatalf("Failed to open GeoIP database: %v", err)
+	}
+	defer geoService.Close()
 
 	emailService := services.NewEmailService(cfg)
-	auditService := services.NewAuditService(auditRepo)
-	authService := services.NewAuthService(cfg, userRepo, tokenRepo, roleRepo, emailService, auditService)
-	userService := services.NewUserService(userRepo, roleRepo, auditService)
-	roleService := services.NewRoleService(roleRepo)
+	auditService := services.NewAuditService(auditRepo, geoService)
+	consentService := services.NewConsentService(consentRepo, auditService)
+	loginThrottler := services.NewLoginThrottler(redisClient, cfg)
+	tokenDenylist := services.NewTokenDenylist(redisClient, cfg)
+	securityStamps := services.NewSecurityStamps(redisClient, userRepo, cfg)
+	authService := services.NewAuthService(cfg, userRepo, tokenRepo, roleRepo, orgRepo, deviceRepo, geoService, emailService, auditService, consentService, loginThrottler, tokenDenylist, securityStamps)
+	userService := services.NewUserService(userRepo, roleRepo, orgRepo, permissionRepo, tokenRepo, securityStamps, loginThrottler, auditService, metadataValidator)
+	roleService := services.NewRoleService(roleRepo, securityStamps, auditService)
+	authzService := services.NewAuthzService(permissionRepo, roleRepo, userRepo, policyEngine, auditService)
+	sessionService := services.NewSessionService(userRepo, orgRepo, tokenRepo, tokenDenylist, auditService)
+	accountService := services.NewAccountService(cfg, userRepo, roleRepo, orgRepo, tokenRepo, securityStamps, auditRepo, consentRepo, deviceRepo, auditService)
+	dormancyService := services.NewDormancyService(cfg, userRepo, orgRepo, roleRepo, tokenRepo, securityStamps, emailService, auditService)
+	organizationService := services.NewOrganizationService(orgRepo, roleRepo, tokenRepo, tokenDenylist, auditService)
+	groupService := services.NewGroupService(groupRepo, orgRepo, roleRepo, securityStamps, auditService)
+	relationService := services.NewRelationService(relationRepo, groupRepo, roleRepo, relationNamespaces, auditService)
+	elevationService := services.NewElevationService(cfg, elevationRepo, roleRepo, orgRepo, securityStamps, auditService)
+	decisionService := services.NewDecisionService(cfg, userRepo, orgRepo, tokenDenylist, authzService, auditService)
+	invitationService := services.NewInvitationService(cfg, invitationRepo, userRepo, roleRepo, orgRepo, emailService, auditService)
 
 	jwtManager := utils.NewJWTManager(cfg.JWTSigningKey, cfg.AccessTokenExpiry)
-	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager, tokenDenylist, securityStamps, authzService)
 	rateLimiter := middleware.NewRateLimiter(redisClient, cfg.RateLimitRequests, cfg.RateLimitWindow)
 
 	authHandler := handlers.NewAuthHandler(authService)
@@ -53,6 +97,24 @@ func main() {
 	roleHandler := handlers.NewRoleHandler(roleService)
 	auditHandler := handlers.NewAuditHandler(auditService)
 	healthHandler := handlers.NewHealthHandler(db, redisClient)
+	accountHandler := handlers.NewAccountHandler(accountService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+	consentHandler := handlers.NewConsentHandler(consentService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	permissionHandler := handlers.NewPermissionHandler(authzService)
+	organizationHandler := handlers.NewOrganizationHandler(organizationService, invitationService)
+	groupHandler := handlers.NewGroupHandler(groupService)
+	authzHandler := handlers.NewAuthzHandler(decisionService)
+	relationHandler := handlers.NewRelationHandler(relationService)
+	elevationHandler := handlers.NewElevationHandler(elevationService)
+
+	scheduler := jobs.NewScheduler()
+	scheduler.Every("account-erasure", cfg.AccountErasureInterval, accountService.ProcessScheduledErasures)
+	scheduler.Every("dormant-accounts", cfg.DormancyCheckInterval, dormancyService.ProcessDormantAccounts)
+	scheduler.Every("role-expiry", cfg.RoleExpirySweepInterval, userService.ProcessExpiredRoleGrants)
+	scheduler.Every("policy-reload", cfg.PolicyReloadInterval, policyEngine.Reload)
+	scheduler.Start()
+	defer scheduler.Stop()
 
 	e := echo.New()
 	e.HideBanner = true
@@ -77,32 +139,117 @@ func main() {
 	auth.POST("/login", authHandler.Login, rateLimiter.LimitByEndpoint("login"))
 	auth.POST("/refresh", authHandler.Refresh)
 	auth.POST("/logout", authHandler.Logout, authMiddleware.Authenticate)
+	auth.POST("/switch-organization", authHandler.SwitchOrganization, authMiddleware.Authenticate)
 	auth.POST("/forgot-password", authHandler.ForgotPassword, rateLimiter.LimitByEndpoint("forgot-password"))
 	auth.POST("/reset-password", authHandler.ResetPassword)
+	auth.GET("/not-me", authHandler.NotMeConfirm, rateLimiter.LimitByEndpoint("not-me"))
+	auth.POST("/not-me", authHandler.NotMe, rateLimiter.LimitByEndpoint("not-me"))
+	auth.POST("/accept-invitation", invitationHandler.AcceptInvitation, rateLimiter.LimitByEndpoint("accept-invitation"))
+
+	userResource := middleware.ResourceParam("user", "id")
 
 	users := api.Group("/users")
 	users.Use(authMiddleware.Authenticate)
 	users.GET("/me", userHandler.GetCurrentUser)
 	users.PUT("/me/password", userHandler.ChangePassword)
-	users.GET("/:id", userHandler.GetUser, authMiddleware.RequireRoles("admin", "auditor"))
-	users.GET("", userHandler.ListUsers, authMiddleware.RequireRoles("admin"))
-	users.POST("", userHandler.CreateUser, authMiddleware.RequireRoles("admin"))
-	users.PUT("/:id", userHandler.UpdateUser, authMiddleware.RequireRoles("admin"))
-	users.DELETE("/:id", userHandler.DeleteUser, authMiddleware.RequireRoles("admin"))
-	users.POST("/:id/roles", userHandler.AssignRole, authMiddleware.RequireRoles("admin"))
-	users.DELETE("/:id/roles/:role", userHandler.UnassignRole, authMiddleware.RequireRoles("admin"))
+	users.PUT("/me/metadata", userHandler.UpdateCurrentUserMetadata)
+	users.GET("/me/permissions", permissionHandler.GetMyPermissions)
+	users.GET("/me/sessions", sessionHandler.ListMySessions)
+	users.DELETE("/me/sessions/:id", sessionHandler.RevokeMySession)
+	users.GET("/me/consents", consentHandler.ListMyConsents)
+	users.POST("/me/consents", consentHandler.AcceptConsents)
+	users.GET("/me/export", accountHandler.ExportData)
+	users.DELETE("/me", accountHandler.DeleteAccount)
+	users.POST("/me/cancel-deletion", accountHandler.CancelDeletion)
+	users.GET("/invitations", invitationHandler.ListInvitations, authMiddleware.RequirePermission("users:invite"))
+	users.POST("/invitations", invitationHandler.CreateInvitation, authMiddleware.RequirePermission("users:invite"))
+	users.POST("/invitations/:id/resend", invitationHandler.ResendInvitation, authMiddleware.RequirePermission("users:invite"))
+	users.DELETE("/invitations/:id", invitationHandler.RevokeInvitation, authMiddleware.RequirePermission("users:invite"))
+	users.GET("/:id", userHandler.GetUser, authMiddleware.RequirePolicy("users:read", userResource))
+	users.GET("", userHandler.ListUsers, authMiddleware.RequirePermission("users:read"))
+	users.POST("", userHandler.CreateUser, authMiddleware.RequirePermission("users:write"))
+	users.PUT("/:id", userHandler.UpdateUser, authMiddleware.RequirePolicy("users:write", userResource))
+	users.DELETE("/:id", userHandler.DeleteUser, authMiddleware.RequirePolicy("users:write", userResource))
+	users.POST("/:id/suspend", userHandler.SuspendUser, authMiddleware.RequirePolicy("users:write", userResource))
+	users.POST("/:id/unsuspend", userHandler.UnsuspendUser, authMiddleware.RequirePolicy("users:write", userResource))
+	users.POST("/:id/unlock", userHandler.UnlockUser, authMiddleware.RequirePolicy("users:write", userResource))
+	users.GET("/:id/sessions", sessionHandler.ListUserSessions, authMiddleware.RequirePolicy("users:read", userResource))
+	users.DELETE("/:id/sessions", sessionHandler.RevokeAllUserSessions, authMiddleware.RequirePolicy("users:write", userResource))
+	users.DELETE("/:id/sessions/:sid", sessionHandler.RevokeUserSession, authMiddleware.RequirePolicy("users:write", userResource))
+	users.POST("/:id/roles", userHandler.AssignRole, authMiddleware.RequirePolicy("roles:delegate", userResource))
+	users.DELETE("/:id/roles/:role", userHandler.UnassignRole, authMiddleware.RequirePolicy("roles:delegate", userResource))
 
 	roles := api.Group("/roles")
 	roles.Use(authMiddleware.Authenticate)
 	roles.GET("", roleHandler.ListRoles)
 	roles.GET("/:id", roleHandler.GetRole)
-	roles.POST("", roleHandler.CreateRole, authMiddleware.RequireRoles("admin"))
-	roles.PUT("/:id", roleHandler.UpdateRole, authMiddleware.RequireRoles("admin"))
-	roles.DELETE("/:id", roleHandler.DeleteRole, authMiddleware.RequireRoles("admin"))
+	roles.POST("", roleHandler.CreateRole, authMiddleware.RequirePlatformPermission("roles:write"))
+	roles.PUT("/:id", roleHandler.UpdateRole, authMiddleware.RequirePlatformPermission("roles:write"))
+	roles.DELETE("/:id", roleHandler.DeleteRole, authMiddleware.RequirePlatformPermission("roles:write"))
+	roles.GET("/:id/permissions", permissionHandler.ListRolePermissions)
+	roles.POST("/:id/permissions", permissionHandler.GrantPermission, authMiddleware.RequirePlatformPermission("permissions:manage"))
+	roles.DELETE("/:id/permissions/:permission", permissionHandler.RevokePermission, authMiddleware.RequirePlatformPermission("permissions:manage"))
+
+	permissions := api.Group("/permissions")
+	permissions.Use(authMiddleware.Authenticate)
+	permissions.GET("", permissionHandler.ListPermissions)
+	permissions.POST("", permissionHandler.CreatePermission, authMiddleware.RequirePlatformPermission("permissions:manage"))
+	permissions.DELETE("/:id", permissionHandler.DeletePermission, authMiddleware.RequirePlatformPermission("permissions:manage"))
+
+	organizations := api.Group("/organizations")
+	organizations.Use(authMiddleware.Authenticate)
+	organizations.GET("", organizationHandler.ListMyOrganizations)
+	organizations.POST("", organizationHandler.CreateOrganization, authMiddleware.RequirePermission("organizations:create"))
+	organizations.GET("/current", organizationHandler.GetCurrentOrganization)
+	organizations.POST("/join", organizationHandler.JoinOrganization)
+	organizations.POST("/current/members", organizationHandler.AddMember, authMiddleware.RequirePermission("users:write"))
+	organizations.DELETE("/current/members/:userId", organizationHandler.RemoveMember, authMiddleware.RequirePermission("users:write"))
+
+	groups := api.Group("/groups")
+	groups.Use(authMiddleware.Authenticate)
+	groups.GET("", groupHandler.ListGroups, authMiddleware.RequirePermission("users:read"))
+	groups.GET("/:id", groupHandler.GetGroup, authMiddleware.RequirePermission("users:read"))
+	groups.POST("", groupHandler.CreateGroup, authMiddleware.RequirePermission("groups:manage"))
+	groups.PUT("/:id", groupHandler.UpdateGroup, authMiddleware.RequirePermission("groups:manage"))
+	groups.DELETE("/:id", groupHandler.DeleteGroup, authMiddleware.RequirePermission("groups:manage"))
+	groups.GET("/:id/members", groupHandler.ListMembers, authMiddleware.RequirePermission("users:read"))
+	groups.POST("/:id/members", groupHandler.AddMembers, authMiddleware.RequirePermission("groups:manage"))
+	groups.DELETE("/:id/members/:userId", groupHandler.RemoveMember, authMiddleware.RequirePermission("groups:manage"))
+	groups.POST("/:id/roles", groupHandler.AssignRole, authMiddleware.RequirePermission("groups:manage", "roles:assign"))
+	groups.DELETE("/:id/roles/:role", groupHandler.UnassignRole, authMiddleware.RequirePermission("groups:manage", "roles:assign"))
+
+	authz := api.Group("/authz")
+	authz.Use(authMiddleware.Authenticate)
+	authz.Use(authMiddleware.RequirePlatformPermission("authz:check"))
+	authz.POST("/check", authzHandler.Check)
+	authz.POST("/check/batch", authzHandler.CheckBatch)
+
+	relations := api.Group("/relations")
+	relations.Use(authMiddleware.Authenticate)
+	relations.GET("/tuples", relationHandler.ListTuples, authMiddleware.RequirePermission("relations:read"))
+	relations.POST("/tuples", relationHandler.WriteTuples, authMiddleware.RequirePermission("relations:manage"))
+	relations.POST("/check", relationHandler.Check, authMiddleware.RequirePermission("relations:read"))
+	relations.POST("/expand", relationHandler.Expand, authMiddleware.RequirePermission("relations:read"))
+	relations.POST("/list-objects", relationHandler.ListObjects, authMiddleware.RequirePermission("relations:read"))
+
+	elevations := api.Group("/elevations")
+	elevations.Use(authMiddleware.Authenticate)
+	elevations.POST("", elevationHandler.RequestElevation)
+	elevations.GET("/pending", elevationHandler.ListPending)
+	elevations.GET("/active", elevationHandler.ListActive)
+	elevations.POST("/:id/approve", elevationHandler.Approve)
+	elevations.POST("/:id/deny", elevationHandler.Deny)
+	elevations.POST("/:id/cancel", elevationHandler.Cancel)
+
+	consents := api.Group("/consents")
+	consents.GET("/current", consentHandler.GetCurrentDocuments)
+	consents.GET("", consentHandler.ListDocuments, authMiddleware.Authenticate, authMiddleware.RequirePlatformPermission("consents:manage"))
+	consents.POST("", consentHandler.PublishDocument, authMiddleware.Authenticate, authMiddleware.RequirePlatformPermission("consents:manage"))
+	consents.GET("/report", consentHandler.Report, authMiddleware.Authenticate, authMiddleware.RequirePlatformPermission("audit:read"))
 
 	audit := api.Group("/audit")
 	audit.Use(authMiddleware.Authenticate)
-	audit.Use(authMiddleware.RequireRoles("admin", "auditor"))
+	audit.Use(authMiddleware.RequirePlatformPermission("audit:read"))
 	audit.GET("", auditHandler.ListAuditLogs)
 
 	fmt.Printf("Server starting on port %s\n", cfg.Port)
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -29,6 +30,45 @@ type Config struct {
 	RateLimitWindow   time.Duration
 	MaxFailedLogins   int
 	LockDuration      time.Duration
+
+	LoginBackoffThreshold   int
+	LoginIPBackoffThreshold int
+	LoginBackoffBase        time.Duration
+	LoginBackoffMax         time.Duration
+	LoginFailureWindow      time.Duration
+
+	AccountDeletionGracePeriod time.Duration
+	AccountErasureInterval     time.Duration
+
+	InvitationExpiry time.Duration
+
+	NewDeviceAlertExpiry time.Duration
+
+	MaxSessionsPerUser int
+	SessionLimitPolicy string
+
+	GeoIPCityDB            string
+	GeoIPASNDB             string
+	ImpossibleTravelSpeed  int
+	ImpossibleTravelAction string
+
+	DormancyThreshold     time.Duration
+	DormancyGracePeriod   time.Duration
+	DormancyCheckInterval time.Duration
+	DormancyExemptEmails  []string
+
+	RoleExpirySweepInterval time.Duration
+
+	UserMetadataSchemaFile string
+
+	PolicyFile           string
+	PolicyReloadInterval time.Duration
+
+	RelationNamespaceFile string
+
+	ElevationApproverRole  string
+	ElevationMaxDuration   time.Duration
+	ElevationRequestExpiry time.Duration
 }
 
 func Load() (*Config, error) {
@@ -53,8 +93,47 @@ func Load() (*Config, error) {
 		SMTPPassword:        getEnv("SMTP_PASSWORD", ""),
 		RateLimitRequests:   rateLimitReqs,
 		RateLimitWindow:     time.Second,
-		MaxFailedLogins:     5,
-		LockDuration:        15 * time.Minute,
+		MaxFailedLogins:     getEnvInt("LOGIN_MAX_FAILED_ATTEMPTS", 5),
+		LockDuration:        getEnvDuration("LOGIN_LOCK_DURATION", 15*time.Minute),
+
+		LoginBackoffThreshold:   getEnvInt("LOGIN_BACKOFF_THRESHOLD", 3),
+		LoginIPBackoffThreshold: getEnvInt("LOGIN_IP_BACKOFF_THRESHOLD", 20),
+		LoginBackoffBase:        getEnvDuration("LOGIN_BACKOFF_BASE", time.Second),
+		LoginBackoffMax:         getEnvDuration("LOGIN_BACKOFF_MAX", 15*time.Minute),
+		LoginFailureWindow:      getEnvDuration("LOGIN_FAILURE_WINDOW", time.Hour),
+
+		AccountDeletionGracePeriod: getEnvDuration("ACCOUNT_DELETION_GRACE_PERIOD", 14*24*time.Hour),
+		AccountErasureInterval:     getEnvDuration("ACCOUNT_ERASURE_INTERVAL", time.Hour),
+
+		InvitationExpiry: getEnvDuration("INVITATION_EXPIRY", 72*time.Hour),
+
+		NewDeviceAlertExpiry: getEnvDuration("NEW_DEVICE_ALERT_EXPIRY", 72*time.Hour),
+
+		MaxSessionsPerUser: getEnvInt("MAX_SESSIONS_PER_USER", 0),
+		SessionLimitPolicy: getEnv("SESSION_LIMIT_POLICY", "evict_oldest"),
+
+		GeoIPCityDB:            getEnv("GEOIP_CITY_DB", ""),
+		GeoIPASNDB:             getEnv("GEOIP_ASN_DB", ""),
+		ImpossibleTravelSpeed:  getEnvInt("IMPOSSIBLE_TRAVEL_SPEED_KMH", 1000),
+		ImpossibleTravelAction: getEnv("IMPOSSIBLE_TRAVEL_ACTION", "step_up"),
+
+		DormancyThreshold:     getEnvDuration("DORMANT_ACCOUNT_THRESHOLD", 90*24*time.Hour),
+		DormancyGracePeriod:   getEnvDuration("DORMANT_ACCOUNT_GRACE_PERIOD", 14*24*time.Hour),
+		DormancyCheckInterval: getEnvDuration("DORMANT_ACCOUNT_CHECK_INTERVAL", 24*time.Hour),
+		DormancyExemptEmails:  getEnvList("DORMANT_ACCOUNT_EXEMPT_EMAILS"),
+
+		RoleExpirySweepInterval: getEnvDuration("ROLE_EXPIRY_SWEEP_INTERVAL", 5*time.Minute),
+
+		UserMetadataSchemaFile: getEnv("USER_METADATA_SCHEMA_FILE", ""),
+
+		PolicyFile:           getEnv("POLICY_FILE", ""),
+		PolicyReloadInterval: getEnvDuration("POLICY_RELOAD_INTERVAL", 30*time.Second),
+
+		RelationNamespaceFile: getEnv("RELATION_NAMESPACE_FILE", ""),
+
+		ElevationApproverRole:  getEnv("ELEVATION_APPROVER_ROLE", "admin"),
+		ElevationMaxDuration:   getEnvDuration("ELEVATION_MAX_DURATION", 8*time.Hour),
+		ElevationRequestExpiry: getEnvDuration("ELEVATION_REQUEST_EXPIRY", 24*time.Hour),
 	}, nil
 }
 
@@ -64,3 +143,31 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type AccountHandler struct {
+	accountService *services.AccountService
+}
+
+func NewAccountHandler(accountService *services.AccountService) *AccountHandler {
+	return &AccountHandler{accountService: accountService}
+}
+
+func (h *AccountHandler) ExportData(c echo.Context) error {
+	userID, ok := c.Get("user_id").(uuid.UUID)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNAUTHORIZED",
+				"message": "User not authenticated",
+			},
+		})
+	}
+
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+
+	export, err := h.accountService.ExportData(userID, ip, userAgent)
+	if err != nil {
+		if err == services.ErrUserNotFound {
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "USER_NOT_FOUND",
+					"message": "User not found",
+				},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "EXPORT_FAILED",
+				"message": "Failed to export account data",
+			},
+		})
+	}
+
+	filename := fmt.Sprintf("account-export-%s-%s.json", userID, time.Now().UTC().Format("20060102"))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	return c.JSON(http.StatusOK, export)
+}
+
+func (h *AccountHandler) DeleteAccount(c echo.Context) error {
+	userID, ok := c.Get("user_id").(uuid.UUID)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNAUTHORIZED",
+				"message": "User not authenticated",
+			},
+		})
+	}
+
+	var req models.DeleteAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	if req.Password == "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": "Password is required",
+			},
+		})
+	}
+
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+
+	user, err := h.accountService.RequestDeletion(userID, req.Password, ip, userAgent)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidCredentials:
+			return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "INVALID_CREDENTIALS",
+					"message": "Password is incorrect",
+				},
+			})
+		case services.ErrDeletionAlreadyRequested:
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "DELETION_ALREADY_REQUESTED",
+					"message": err.Error(),
+				},
+			})
+		case services.ErrLastAdmin:
+			return lastAdminResponse(c)
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "DELETE_FAILED",
+					"message": "Failed to request account deletion",
+				},
+			})
+		}
+	}
+
+	return c.JSON(http.StatusAccepted, map[string]interface{}{
+		"message":                "Account deletion scheduled. You can cancel it until the scheduled time.",
+		"deletion_scheduled_for": user.DeletionScheduledFor,
+	})
+}
+
+func (h *AccountHandler) CancelDeletion(c echo.Context) error {
+	userID, ok := c.Get("user_id").(uuid.UUID)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNAUTHORIZED",
+				"message": "User not authenticated",
+			},
+		})
+	}
+
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+
+	if err := h.accountService.CancelDeletion(userID, ip, userAgent); err != nil {
+		if err == services.ErrNoDeletionPending {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "NO_DELETION_PENDING",
+					"message": err.Error(),
+				},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "CANCEL_DELETION_FAILED",
+				"message": "Failed to cancel account deletion",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Account deletion cancelled",
+	})
+}
@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"html/template"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/auth-service/internal/models"
 	"github.com/auth-service/internal/services"
@@ -42,11 +47,14 @@ func (h *AuthHandler) Register(c echo.Context) error {
 
 	user, err := h.authService.Register(req, ip, userAgent)
 	if err != nil {
-		if err == services.ErrDuplicateEmail {
-			return c.JSON(http.StatusConflict, map[string]interface{}{
+		if isDuplicateIdentifier(err) {
+			return duplicateIdentifierResponse(c, err)
+		}
+		if err == services.ErrConsentRequired || err == services.ErrConsentVersionMismatch {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
 				"error": map[string]string{
-					"code":    "DUPLICATE_EMAIL",
-					"message": "Email already exists",
+					"code":    "CONSENT_REQUIRED",
+					"message": err.Error(),
 				},
 			})
 		}
@@ -105,6 +113,101 @@ func (h *AuthHandler) VerifyEmail(c echo.Context) error {
 	})
 }
 
+// notMePage is what the "This wasn't me" link in the new-sign-in email
+// opens. Opening it changes nothing, so link scanners and prefetchers that
+// follow it are harmless; only submitting the form signs the device out.
+var notMePage = template.Must(template.New("not-me").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Sign out unrecognized device</title></head>
+<body>
+{{if .Message}}<p>{{.Message}}</p>{{else}}
+<h2>Wasn't you?</h2>
+<p>Signing out will end the session on the unrecognized device, forget it, and email you a link to reset your password.</p>
+<form method="POST" action="/api/v1/auth/not-me">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">Sign out that device</button>
+</form>{{end}}
+</body>
+</html>`))
+
+func renderNotMePage(c echo.Context, status int, token, message string) error {
+	var page strings.Builder
+	if err := notMePage.Execute(&page, map[string]string{"Token": token, "Message": message}); err != nil {
+		return err
+	}
+	return c.HTML(status, page.String())
+}
+
+// NotMeConfirm shows the confirmation form for the emailed link.
+func (h *AuthHandler) NotMeConfirm(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return renderNotMePage(c, http.StatusBadRequest, "", "This link is incomplete. Use the token from the email instead.")
+	}
+	return renderNotMePage(c, http.StatusOK, token, "")
+}
+
+// NotMe signs the device out. It answers the confirmation form with a page
+// and API clients with JSON.
+func (h *AuthHandler) NotMe(c echo.Context) error {
+	fromForm := strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEApplicationForm)
+
+	var req models.NotMeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	if req.Token == "" {
+		if fromForm {
+			return renderNotMePage(c, http.StatusBadRequest, "", "This link is incomplete. Use the token from the email instead.")
+		}
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": "Token is required",
+			},
+		})
+	}
+
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+
+	if err := h.authService.DisownLogin(req.Token, ip, userAgent); err != nil {
+		if fromForm {
+			if err == services.ErrInvalidToken {
+				return renderNotMePage(c, http.StatusBadRequest, "", "This link is invalid or has expired.")
+			}
+			return renderNotMePage(c, http.StatusInternalServerError, "", "Failed to sign out the device. Please try again.")
+		}
+		if err == services.ErrInvalidToken {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "INVALID_TOKEN",
+					"message": "Invalid or expired token",
+				},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "NOT_ME_FAILED",
+				"message": "Failed to sign out the device",
+			},
+		})
+	}
+
+	if fromForm {
+		return renderNotMePage(c, http.StatusOK, "", "The device has been signed out. Check your email to reset your password.")
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "The device has been signed out. Check your email to reset your password.",
+	})
+}
+
 func (h *AuthHandler) Login(c echo.Context) error {
 	var req models.LoginRequest
 	if err := c.Bind(&req); err != nil {
@@ -116,11 +219,11 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		})
 	}
 
-	if req.Email == "" || req.Password == "" {
+	if (req.Identifier == "" && req.Email == "") || req.Password == "" {
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{
 			"error": map[string]string{
 				"code":    "VALIDATION_ERROR",
-				"message": "Email and password are required",
+				"message": "Identifier (email, username or phone) and password are required",
 			},
 		})
 	}
@@ -130,12 +233,23 @@ func (h *AuthHandler) Login(c echo.Context) error {
 
 	response, err := h.authService.Login(req, ip, userAgent)
 	if err != nil {
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			return c.JSON(http.StatusTooManyRequests, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "LOGIN_THROTTLED",
+					"message": "Too many failed login attempts. Please try again later.",
+				},
+			})
+		}
+
 		switch err {
 		case services.ErrInvalidCredentials:
 			return c.JSON(http.StatusUnauthorized, map[string]interface{}{
 				"error": map[string]string{
 					"code":    "INVALID_CREDENTIALS",
-					"message": "Invalid email or password",
+					"message": "Invalid login or password",
 				},
 			})
 		case services.ErrUserNotVerified:
@@ -152,6 +266,41 @@ func (h *AuthHandler) Login(c echo.Context) error {
 					"message": "Account is temporarily locked due to too many failed attempts",
 				},
 			})
+		case services.ErrAccountSuspended:
+			return c.JSON(http.StatusForbidden, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "ACCOUNT_SUSPENDED",
+					"message": "Account has been suspended by an administrator",
+				},
+			})
+		case services.ErrStepUpRequired:
+			return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "STEP_UP_REQUIRED",
+					"message": "Sign-in from an unusual location. Enter the code sent to your email as step_up_code.",
+				},
+			})
+		case services.ErrImpossibleTravel:
+			return c.JSON(http.StatusForbidden, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "LOGIN_BLOCKED_LOCATION",
+					"message": "Sign-in blocked from an unusual location",
+				},
+			})
+		case services.ErrSessionLimit:
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "SESSION_LIMIT_REACHED",
+					"message": "Maximum number of active sessions reached. Sign out of another device first.",
+				},
+			})
+		case services.ErrNotOrgMember:
+			return c.JSON(http.StatusForbidden, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "NOT_ORGANIZATION_MEMBER",
+					"message": "You are not a member of this organization",
+				},
+			})
 		default:
 			return c.JSON(http.StatusUnauthorized, map[string]interface{}{
 				"error": map[string]string{
@@ -201,6 +350,76 @@ func (h *AuthHandler) Refresh(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+func (h *AuthHandler) SwitchOrganization(c echo.Context) error {
+	userID, ok := c.Get("user_id").(uuid.UUID)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNAUTHORIZED",
+				"message": "User not authenticated",
+			},
+		})
+	}
+
+	var req models.SwitchOrganizationRequest
+	if err := c.Bind(&req); err != nil || req.OrganizationID == uuid.Nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": "organization_id is required",
+			},
+		})
+	}
+
+	sessionID, _ := c.Get("session_id").(uuid.UUID)
+	tokenID, _ := c.Get("token_id").(string)
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+
+	response, err := h.authService.SwitchOrganization(userID, sessionID, tokenID, req.OrganizationID, ip, userAgent)
+	if err != nil {
+		switch err {
+		case services.ErrAccountInactive:
+			return c.JSON(http.StatusForbidden, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "ACCOUNT_INACTIVE",
+					"message": "Account is deactivated",
+				},
+			})
+		case services.ErrAccountSuspended:
+			return c.JSON(http.StatusForbidden, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "ACCOUNT_SUSPENDED",
+					"message": "Account has been suspended by an administrator",
+				},
+			})
+		case services.ErrNotOrgMember:
+			return c.JSON(http.StatusForbidden, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "NOT_ORGANIZATION_MEMBER",
+					"message": "You are not a member of this organization",
+				},
+			})
+		case services.ErrSessionNotFound, services.ErrInvalidToken:
+			return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "SESSION_NOT_FOUND",
+					"message": "Session has ended; sign in again",
+				},
+			})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "SWITCH_FAILED",
+					"message": "Failed to switch organization",
+				},
+			})
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
 func (h *AuthHandler) Logout(c echo.Context) error {
 	userID, ok := c.Get("user_id").(uuid.UUID)
 	if !ok {
@@ -212,10 +431,12 @@ func (h *AuthHandler) Logout(c echo.Context) error {
 		})
 	}
 
+	sessionID, _ := c.Get("session_id").(uuid.UUID)
+	all := c.QueryParam("all") == "true"
 	ip := c.RealIP()
 	userAgent := c.Request().UserAgent()
 
-	if err := h.authService.Logout(userID, ip, userAgent); err != nil {
+	if err := h.authService.Logout(userID, sessionID, all, ip, userAgent); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
 			"error": map[string]string{
 				"code":    "LOGOUT_FAILED",
@@ -286,3 +507,24 @@ func (h *AuthHandler) ResetPassword(c echo.Context) error {
 		"message": "Password reset successfully",
 	})
 }
+
+var duplicateIdentifierErrors = map[error][2]string{
+	services.ErrDuplicateEmail:    {"DUPLICATE_EMAIL", "Email already exists"},
+	services.ErrDuplicateUsername: {"DUPLICATE_USERNAME", "Username already exists"},
+	services.ErrDuplicatePhone:    {"DUPLICATE_PHONE", "Phone already exists"},
+}
+
+func isDuplicateIdentifier(err error) bool {
+	_, ok := duplicateIdentifierErrors[err]
+	return ok
+}
+
+func duplicateIdentifierResponse(c echo.Context, err error) error {
+	e := duplicateIdentifierErrors[err]
+	return c.JSON(http.StatusConflict, map[string]interface{}{
+		"error": map[string]string{
+			"code":    e[0],
+			"message": e[1],
+		},
+	})
+}
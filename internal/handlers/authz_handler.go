@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type AuthzHandler struct {
+	decisionService *services.DecisionService
+}
+
+func NewAuthzHandler(decisionService *services.DecisionService) *AuthzHandler {
+	return &AuthzHandler{decisionService: decisionService}
+}
+
+func (h *AuthzHandler) Check(c echo.Context) error {
+	var req models.AuthzCheckRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	checkedBy, _ := c.Get("user_id").(uuid.UUID)
+
+	decision, err := h.decisionService.Check(req, checkedBy, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return authzCheckError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, decision)
+}
+
+func (h *AuthzHandler) CheckBatch(c echo.Context) error {
+	var req models.AuthzBatchCheckRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	checkedBy, _ := c.Get("user_id").(uuid.UUID)
+
+	decisions, err := h.decisionService.CheckBatch(req, checkedBy, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return authzCheckError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": decisions,
+	})
+}
+
+func authzCheckError(c echo.Context, err error) error {
+	switch err {
+	case services.ErrInvalidAuthzCheck, services.ErrAuthzBatchTooLarge:
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": err.Error(),
+			},
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+		"error": map[string]string{
+			"code":    "CHECK_FAILED",
+			"message": "Failed to evaluate authorization",
+		},
+	})
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type ConsentHandler struct {
+	consentService *services.ConsentService
+}
+
+func NewConsentHandler(consentService *services.ConsentService) *ConsentHandler {
+	return &ConsentHandler{consentService: consentService}
+}
+
+func (h *ConsentHandler) GetCurrentDocuments(c echo.Context) error {
+	docs, err := h.consentService.CurrentDocuments()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "LIST_FAILED",
+				"message": "Failed to list consent documents",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": docs,
+	})
+}
+
+func (h *ConsentHandler) ListDocuments(c echo.Context) error {
+	docs, err := h.consentService.ListDocuments()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "LIST_FAILED",
+				"message": "Failed to list consent documents",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": docs,
+	})
+}
+
+func (h *ConsentHandler) PublishDocument(c echo.Context) error {
+	var req models.PublishConsentDocumentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	if req.Type == "" || req.Version == "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": "Type and version are required",
+			},
+		})
+	}
+
+	publishedBy, _ := c.Get("user_id").(uuid.UUID)
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+
+	doc, err := h.consentService.PublishDocument(req, publishedBy, ip, userAgent)
+	if err != nil {
+		if err == services.ErrConsentDocumentExists {
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "DOCUMENT_EXISTS",
+					"message": err.Error(),
+				},
+			})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "PUBLISH_FAILED",
+				"message": err.Error(),
+			},
+		})
+	}
+
+	return c.JSON(http.StatusCreated, doc)
+}
+
+func (h *ConsentHandler) Report(c echo.Context) error {
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	perPage, _ := strconv.Atoi(c.QueryParam("per_page"))
+	documentID, _ := strconv.Atoi(c.QueryParam("document_id"))
+
+	result, err := h.consentService.Report(documentID, page, perPage)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "REPORT_FAILED",
+				"message": "Failed to build consent report",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *ConsentHandler) ListMyConsents(c echo.Context) error {
+	userID, ok := c.Get("user_id").(uuid.UUID)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNAUTHORIZED",
+				"message": "User not authenticated",
+			},
+		})
+	}
+
+	consents, err := h.consentService.UserConsents(userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "LIST_FAILED",
+				"message": "Failed to list consents",
+			},
+		})
+	}
+
+	pending, err := h.consentService.PendingForUser(userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "LIST_FAILED",
+				"message": "Failed to list consents",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data":    consents,
+		"pending": pending,
+	})
+}
+
+func (h *ConsentHandler) AcceptConsents(c echo.Context) error {
+	userID, ok := c.Get("user_id").(uuid.UUID)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNAUTHORIZED",
+				"message": "User not authenticated",
+			},
+		})
+	}
+
+	var req models.AcceptConsentsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	if len(req.DocumentIDs) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": "document_ids is required",
+			},
+		})
+	}
+
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+
+	if err := h.consentService.Accept(userID, req.DocumentIDs, ip, userAgent); err != nil {
+		if err == services.ErrConsentDocumentInvalid {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "INVALID_DOCUMENT",
+					"message": err.Error(),
+				},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "ACCEPT_FAILED",
+				"message": "Failed to record consent",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Consent recorded successfully",
+	})
+}
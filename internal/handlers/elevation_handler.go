@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type ElevationHandler struct {
+	elevationService *services.ElevationService
+}
+
+func NewElevationHandler(elevationService *services.ElevationService) *ElevationHandler {
+	return &ElevationHandler{elevationService: elevationService}
+}
+
+func (h *ElevationHandler) RequestElevation(c echo.Context) error {
+	var req models.CreateElevationRequest
+	if err := c.Bind(&req); err != nil {
+		return invalidElevationRequest(c)
+	}
+
+	userID, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	e, err := h.elevationService.RequestElevation(req, orgID, userID, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return elevationError(c, err, "REQUEST_FAILED")
+	}
+
+	return c.JSON(http.StatusCreated, e)
+}
+
+func (h *ElevationHandler) ListPending(c echo.Context) error {
+	userID, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	requests, err := h.elevationService.ListPending(orgID, userID)
+	if err != nil {
+		return elevationError(c, err, "LIST_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": requests,
+	})
+}
+
+func (h *ElevationHandler) ListActive(c echo.Context) error {
+	userID, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	requests, err := h.elevationService.ListActive(orgID, userID)
+	if err != nil {
+		return elevationError(c, err, "LIST_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": requests,
+	})
+}
+
+func (h *ElevationHandler) Approve(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return invalidElevationID(c)
+	}
+
+	var req models.ElevationDecisionRequest
+	if err := c.Bind(&req); err != nil {
+		return invalidElevationRequest(c)
+	}
+
+	approverID, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	e, err := h.elevationService.Approve(id, orgID, approverID, req.Note, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return elevationError(c, err, "APPROVE_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, e)
+}
+
+func (h *ElevationHandler) Deny(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return invalidElevationID(c)
+	}
+
+	var req models.ElevationDecisionRequest
+	if err := c.Bind(&req); err != nil {
+		return invalidElevationRequest(c)
+	}
+
+	approverID, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	e, err := h.elevationService.Deny(id, orgID, approverID, req.Note, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return elevationError(c, err, "DENY_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, e)
+}
+
+func (h *ElevationHandler) Cancel(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return invalidElevationID(c)
+	}
+
+	userID, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	if err := h.elevationService.Cancel(id, orgID, userID, c.RealIP(), c.Request().UserAgent()); err != nil {
+		return elevationError(c, err, "CANCEL_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Elevation request cancelled successfully",
+	})
+}
+
+func invalidElevationRequest(c echo.Context) error {
+	return c.JSON(http.StatusBadRequest, map[string]interface{}{
+		"error": map[string]string{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request body",
+		},
+	})
+}
+
+func invalidElevationID(c echo.Context) error {
+	return c.JSON(http.StatusBadRequest, map[string]interface{}{
+		"error": map[string]string{
+			"code":    "INVALID_ID",
+			"message": "Invalid elevation request ID format",
+		},
+	})
+}
+
+func elevationError(c echo.Context, err error, fallbackCode string) error {
+	if errors.Is(err, services.ErrRoleNotManageable) {
+		return roleNotManageableResponse(c, err)
+	}
+	if errors.Is(err, services.ErrInvalidElevation) {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": err.Error(),
+			},
+		})
+	}
+
+	switch err {
+	case services.ErrElevationNotFound:
+		return c.JSON(http.StatusNotFound, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "ELEVATION_NOT_FOUND",
+				"message": "Elevation request not found",
+			},
+		})
+	case services.ErrRoleNotFound:
+		return c.JSON(http.StatusNotFound, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "ROLE_NOT_FOUND",
+				"message": "Role not found",
+			},
+		})
+	case services.ErrUserNotFound:
+		return c.JSON(http.StatusNotFound, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "USER_NOT_FOUND",
+				"message": "Requester is no longer a member of this organization",
+			},
+		})
+	case services.ErrNotElevationApprover, services.ErrSelfApproval:
+		return c.JSON(http.StatusForbidden, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "FORBIDDEN",
+				"message": err.Error(),
+			},
+		})
+	case services.ErrElevationExists:
+		return c.JSON(http.StatusConflict, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "ELEVATION_EXISTS",
+				"message": err.Error(),
+			},
+		})
+	case services.ErrElevationNotPending:
+		return c.JSON(http.StatusConflict, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "ELEVATION_NOT_PENDING",
+				"message": err.Error(),
+			},
+		})
+	case services.ErrRoleAlreadyHeld:
+		return c.JSON(http.StatusConflict, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "ROLE_ALREADY_HELD",
+				"message": err.Error(),
+			},
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+		"error": map[string]string{
+			"code":    fallbackCode,
+			"message": "Failed to process elevation request",
+		},
+	})
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type GroupHandler struct {
+	groupService *services.GroupService
+}
+
+func NewGroupHandler(groupService *services.GroupService) *GroupHandler {
+	return &GroupHandler{groupService: groupService}
+}
+
+func (h *GroupHandler) ListGroups(c echo.Context) error {
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	groups, err := h.groupService.ListGroups(orgID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "LIST_FAILED",
+				"message": "Failed to list groups",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": groups,
+	})
+}
+
+func (h *GroupHandler) GetGroup(c echo.Context) error {
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return invalidGroupID(c)
+	}
+
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	group, err := h.groupService.GetGroup(orgID, groupID)
+	if err != nil {
+		return groupError(c, err, "GET_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, group)
+}
+
+func (h *GroupHandler) CreateGroup(c echo.Context) error {
+	var req models.CreateGroupRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	createdBy, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	group, err := h.groupService.CreateGroup(orgID, req, createdBy, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return groupError(c, err, "CREATE_FAILED")
+	}
+
+	return c.JSON(http.StatusCreated, group)
+}
+
+func (h *GroupHandler) UpdateGroup(c echo.Context) error {
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return invalidGroupID(c)
+	}
+
+	var req models.UpdateGroupRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	updatedBy, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	group, err := h.groupService.UpdateGroup(orgID, groupID, req, updatedBy, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return groupError(c, err, "UPDATE_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, group)
+}
+
+func (h *GroupHandler) DeleteGroup(c echo.Context) error {
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return invalidGroupID(c)
+	}
+
+	deletedBy, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	if err := h.groupService.DeleteGroup(orgID, groupID, deletedBy, c.RealIP(), c.Request().UserAgent()); err != nil {
+		return groupError(c, err, "DELETE_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Group deleted successfully",
+	})
+}
+
+func (h *GroupHandler) ListMembers(c echo.Context) error {
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return invalidGroupID(c)
+	}
+
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	members, err := h.groupService.ListMembers(orgID, groupID)
+	if err != nil {
+		return groupError(c, err, "LIST_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": members,
+	})
+}
+
+func (h *GroupHandler) AddMembers(c echo.Context) error {
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return invalidGroupID(c)
+	}
+
+	var req models.AddGroupMembersRequest
+	if err := c.Bind(&req); err != nil || len(req.UserIDs) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": "user_ids is required",
+			},
+		})
+	}
+
+	addedBy, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	added, err := h.groupService.AddMembers(orgID, groupID, req.UserIDs, addedBy, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return groupError(c, err, "ADD_MEMBERS_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"added": added,
+	})
+}
+
+func (h *GroupHandler) RemoveMember(c echo.Context) error {
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return invalidGroupID(c)
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid user ID format",
+			},
+		})
+	}
+
+	removedBy, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	if err := h.groupService.RemoveMember(orgID, groupID, userID, removedBy, c.RealIP(), c.Request().UserAgent()); err != nil {
+		return groupError(c, err, "REMOVE_MEMBER_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Member removed successfully",
+	})
+}
+
+func (h *GroupHandler) AssignRole(c echo.Context) error {
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return invalidGroupID(c)
+	}
+
+	var req models.AssignRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	assignedBy, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	if err := h.groupService.AssignRole(orgID, groupID, req.RoleID, assignedBy, c.RealIP(), c.Request().UserAgent()); err != nil {
+		return groupError(c, err, "ASSIGN_ROLE_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Role assigned successfully",
+	})
+}
+
+func (h *GroupHandler) UnassignRole(c echo.Context) error {
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return invalidGroupID(c)
+	}
+
+	roleID, err := strconv.Atoi(c.Param("role"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid role ID format",
+			},
+		})
+	}
+
+	removedBy, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	if err := h.groupService.UnassignRole(orgID, groupID, roleID, removedBy, c.RealIP(), c.Request().UserAgent()); err != nil {
+		return groupError(c, err, "UNASSIGN_ROLE_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Role unassigned successfully",
+	})
+}
+
+func invalidGroupID(c echo.Context) error {
+	return c.JSON(http.StatusBadRequest, map[string]interface{}{
+		"error": map[string]string{
+			"code":    "INVALID_ID",
+			"message": "Invalid group ID format",
+		},
+	})
+}
+
+func groupError(c echo.Context, err error, fallbackCode string) error {
+	if errors.Is(err, services.ErrRoleNotManageable) {
+		return roleNotManageableResponse(c, err)
+	}
+
+	switch err {
+	case services.ErrGroupNotFound:
+		return c.JSON(http.StatusNotFound, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "GROUP_NOT_FOUND",
+				"message": "Group not found",
+			},
+		})
+	case services.ErrUserNotFound:
+		return c.JSON(http.StatusNotFound, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "USER_NOT_FOUND",
+				"message": "User not found in this organization or group",
+			},
+		})
+	case services.ErrRoleNotFound:
+		return c.JSON(http.StatusNotFound, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "ROLE_NOT_FOUND",
+				"message": "Role not found",
+			},
+		})
+	case services.ErrLastAdmin:
+		return lastAdminResponse(c)
+	case services.ErrGroupExists:
+		return c.JSON(http.StatusConflict, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "GROUP_EXISTS",
+				"message": err.Error(),
+			},
+		})
+	}
+	return c.JSON(http.StatusBadRequest, map[string]interface{}{
+		"error": map[string]string{
+			"code":    fallbackCode,
+			"message": err.Error(),
+		},
+	})
+}
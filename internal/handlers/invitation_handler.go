@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type InvitationHandler struct {
+	invitationService *services.InvitationService
+}
+
+func NewInvitationHandler(invitationService *services.InvitationService) *InvitationHandler {
+	return &InvitationHandler{invitationService: invitationService}
+}
+
+func (h *InvitationHandler) CreateInvitation(c echo.Context) error {
+	var req models.CreateInvitationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	if req.Email == "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": "Email is required",
+			},
+		})
+	}
+
+	invitedBy, _ := c.Get("user_id").(uuid.UUID)
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	inv, err := h.invitationService.CreateInvitation(req, orgID, invitedBy, ip, userAgent)
+	if err != nil {
+		if errors.Is(err, services.ErrRoleNotManageable) {
+			return roleNotManageableResponse(c, err)
+		}
+		switch err {
+		case services.ErrAlreadyOrgMember:
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "ALREADY_MEMBER",
+					"message": err.Error(),
+				},
+			})
+		case services.ErrInvitationExists:
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "INVITATION_EXISTS",
+					"message": err.Error(),
+				},
+			})
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "INVITE_FAILED",
+					"message": err.Error(),
+				},
+			})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, inv)
+}
+
+func (h *InvitationHandler) ListInvitations(c echo.Context) error {
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+	invitations, err := h.invitationService.ListPendingInvitations(orgID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "LIST_FAILED",
+				"message": "Failed to list invitations",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": invitations,
+	})
+}
+
+func (h *InvitationHandler) ResendInvitation(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid invitation ID format",
+			},
+		})
+	}
+
+	resentBy, _ := c.Get("user_id").(uuid.UUID)
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	inv, err := h.invitationService.ResendInvitation(id, orgID, resentBy, ip, userAgent)
+	if err != nil {
+		return invitationError(c, err, "RESEND_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, inv)
+}
+
+func (h *InvitationHandler) RevokeInvitation(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid invitation ID format",
+			},
+		})
+	}
+
+	revokedBy, _ := c.Get("user_id").(uuid.UUID)
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	if err := h.invitationService.RevokeInvitation(id, orgID, revokedBy, ip, userAgent); err != nil {
+		return invitationError(c, err, "REVOKE_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Invitation revoked successfully",
+	})
+}
+
+func (h *InvitationHandler) AcceptInvitation(c echo.Context) error {
+	var req models.AcceptInvitationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	if req.Token == "" || req.Password == "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": "Token and password are required",
+			},
+		})
+	}
+
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+
+	user, err := h.invitationService.AcceptInvitation(req, ip, userAgent)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidToken:
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "INVALID_INVITATION",
+					"message": "Invalid or expired invitation",
+				},
+			})
+		case services.ErrDuplicateEmail:
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "DUPLICATE_EMAIL",
+					"message": "Email already exists",
+				},
+			})
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "ACCEPT_INVITATION_FAILED",
+					"message": err.Error(),
+				},
+			})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "Invitation accepted. You can now log in.",
+		"user": map[string]interface{}{
+			"id":           user.ID,
+			"email":        user.Email,
+			"display_name": user.DisplayName,
+		},
+	})
+}
+
+func invitationError(c echo.Context, err error, fallbackCode string) error {
+	switch err {
+	case services.ErrInvitationNotFound:
+		return c.JSON(http.StatusNotFound, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVITATION_NOT_FOUND",
+				"message": "Invitation not found",
+			},
+		})
+	case services.ErrInvitationNotPending:
+		return c.JSON(http.StatusConflict, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVITATION_NOT_PENDING",
+				"message": err.Error(),
+			},
+		})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    fallbackCode,
+				"message": err.Error(),
+			},
+		})
+	}
+}
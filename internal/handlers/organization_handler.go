@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type OrganizationHandler struct {
+	organizationService *services.OrganizationService
+	invitationService   *services.InvitationService
+}
+
+func NewOrganizationHandler(organizationService *services.OrganizationService, invitationService *services.InvitationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService: organizationService,
+		invitationService:   invitationService,
+	}
+}
+
+func (h *OrganizationHandler) ListMyOrganizations(c echo.Context) error {
+	userID, _ := c.Get("user_id").(uuid.UUID)
+	currentOrgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	memberships, err := h.organizationService.ListMemberships(userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "LIST_FAILED",
+				"message": "Failed to list organizations",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data":                    memberships,
+		"current_organization_id": currentOrgID,
+	})
+}
+
+func (h *OrganizationHandler) CreateOrganization(c echo.Context) error {
+	var req models.CreateOrganizationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	createdBy, _ := c.Get("user_id").(uuid.UUID)
+
+	org, err := h.organizationService.CreateOrganization(req, createdBy, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		if err == services.ErrOrganizationExists {
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "ORGANIZATION_EXISTS",
+					"message": err.Error(),
+				},
+			})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "CREATE_FAILED",
+				"message": err.Error(),
+			},
+		})
+	}
+
+	return c.JSON(http.StatusCreated, org)
+}
+
+func (h *OrganizationHandler) GetCurrentOrganization(c echo.Context) error {
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	org, err := h.organizationService.GetOrganization(orgID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "ORGANIZATION_NOT_FOUND",
+				"message": "Organization not found",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, org)
+}
+
+func (h *OrganizationHandler) AddMember(c echo.Context) error {
+	var req models.AddOrganizationMemberRequest
+	if err := c.Bind(&req); err != nil || req.UserID == uuid.Nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": "user_id is required",
+			},
+		})
+	}
+
+	invitedBy, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	inv, err := h.invitationService.InviteMember(orgID, req, invitedBy, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		if errors.Is(err, services.ErrRoleNotManageable) {
+			return roleNotManageableResponse(c, err)
+		}
+		switch err {
+		case services.ErrUserNotFound, services.ErrRoleNotFound:
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "NOT_FOUND",
+					"message": err.Error(),
+				},
+			})
+		case services.ErrAlreadyOrgMember:
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "ALREADY_MEMBER",
+					"message": err.Error(),
+				},
+			})
+		case services.ErrInvitationExists:
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "INVITATION_EXISTS",
+					"message": err.Error(),
+				},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "ADD_MEMBER_FAILED",
+				"message": "Failed to invite member",
+			},
+		})
+	}
+
+	// The user becomes a member only once they accept the invitation.
+	return c.JSON(http.StatusAccepted, inv)
+}
+
+func (h *OrganizationHandler) JoinOrganization(c echo.Context) error {
+	var req models.JoinOrganizationRequest
+	if err := c.Bind(&req); err != nil || req.Token == "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": "token is required",
+			},
+		})
+	}
+
+	userID, _ := c.Get("user_id").(uuid.UUID)
+
+	org, err := h.invitationService.JoinOrganization(req.Token, userID, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		switch err {
+		case services.ErrInvalidToken:
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "INVALID_TOKEN",
+					"message": "Invitation is invalid or has expired",
+				},
+			})
+		case services.ErrAlreadyOrgMember:
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "ALREADY_MEMBER",
+					"message": err.Error(),
+				},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "JOIN_FAILED",
+				"message": "Failed to join organization",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, org)
+}
+
+func (h *OrganizationHandler) RemoveMember(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid user ID format",
+			},
+		})
+	}
+
+	removedBy, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	if err := h.organizationService.RemoveMember(orgID, userID, removedBy, c.RealIP(), c.Request().UserAgent()); err != nil {
+		if err == services.ErrLastAdmin {
+			return lastAdminResponse(c)
+		}
+		if err == services.ErrUserNotFound {
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "USER_NOT_FOUND",
+					"message": "User is not a member of this organization",
+				},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "REMOVE_MEMBER_FAILED",
+				"message": "Failed to remove member",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Member removed successfully",
+	})
+}
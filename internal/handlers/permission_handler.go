@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type PermissionHandler struct {
+	authzService *services.AuthzService
+}
+
+func NewPermissionHandler(authzService *services.AuthzService) *PermissionHandler {
+	return &PermissionHandler{authzService: authzService}
+}
+
+func (h *PermissionHandler) ListPermissions(c echo.Context) error {
+	permissions, err := h.authzService.ListPermissions()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "LIST_FAILED",
+				"message": "Failed to list permissions",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": permissions,
+	})
+}
+
+func (h *PermissionHandler) CreatePermission(c echo.Context) error {
+	var req models.CreatePermissionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	actorID, _ := c.Get("user_id").(uuid.UUID)
+
+	permission, err := h.authzService.CreatePermission(req, actorID, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		switch err {
+		case services.ErrPermissionExists:
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "PERMISSION_EXISTS",
+					"message": err.Error(),
+				},
+			})
+		case services.ErrInvalidPermission:
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "VALIDATION_ERROR",
+					"message": err.Error(),
+				},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "CREATE_FAILED",
+				"message": "Failed to create permission",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusCreated, permission)
+}
+
+func (h *PermissionHandler) DeletePermission(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid permission ID format",
+			},
+		})
+	}
+
+	actorID, _ := c.Get("user_id").(uuid.UUID)
+
+	if err := h.authzService.DeletePermission(id, actorID, c.RealIP(), c.Request().UserAgent()); err != nil {
+		return permissionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Permission deleted successfully",
+	})
+}
+
+func (h *PermissionHandler) ListRolePermissions(c echo.Context) error {
+	roleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid role ID format",
+			},
+		})
+	}
+
+	permissions, err := h.authzService.ListRolePermissions(roleID)
+	if err != nil {
+		return permissionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": permissions,
+	})
+}
+
+func (h *PermissionHandler) GrantPermission(c echo.Context) error {
+	roleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid role ID format",
+			},
+		})
+	}
+
+	var req models.GrantPermissionRequest
+	if err := c.Bind(&req); err != nil || req.Permission == "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Permission name is required",
+			},
+		})
+	}
+
+	actorID, _ := c.Get("user_id").(uuid.UUID)
+
+	if err := h.authzService.GrantPermission(roleID, req.Permission, actorID, c.RealIP(), c.Request().UserAgent()); err != nil {
+		return permissionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Permission granted successfully",
+	})
+}
+
+func (h *PermissionHandler) RevokePermission(c echo.Context) error {
+	roleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid role ID format",
+			},
+		})
+	}
+
+	actorID, _ := c.Get("user_id").(uuid.UUID)
+
+	if err := h.authzService.RevokePermission(roleID, c.Param("permission"), actorID, c.RealIP(), c.Request().UserAgent()); err != nil {
+		return permissionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Permission revoked successfully",
+	})
+}
+
+func (h *PermissionHandler) GetMyPermissions(c echo.Context) error {
+	userID, ok := c.Get("user_id").(uuid.UUID)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNAUTHORIZED",
+				"message": "User not authenticated",
+			},
+		})
+	}
+
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+	permissions, err := h.authzService.EffectivePermissions(userID, orgID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "LIST_FAILED",
+				"message": "Failed to resolve permissions",
+			},
+		})
+	}
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": permissions,
+	})
+}
+
+func permissionError(c echo.Context, err error) error {
+	switch err {
+	case services.ErrRoleNotFound:
+		return c.JSON(http.StatusNotFound, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "ROLE_NOT_FOUND",
+				"message": "Role not found",
+			},
+		})
+	case services.ErrPermissionNotFound:
+		return c.JSON(http.StatusNotFound, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "PERMISSION_NOT_FOUND",
+				"message": "Permission not found",
+			},
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+		"error": map[string]string{
+			"code":    "PERMISSION_UPDATE_FAILED",
+			"message": "Failed to update permissions",
+		},
+	})
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type RelationHandler struct {
+	relationService *services.RelationService
+}
+
+func NewRelationHandler(relationService *services.RelationService) *RelationHandler {
+	return &RelationHandler{relationService: relationService}
+}
+
+func (h *RelationHandler) ListTuples(c echo.Context) error {
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	filter := models.RelationTupleFilter{
+		Namespace: c.QueryParam("namespace"),
+		ObjectID:  c.QueryParam("object_id"),
+		Relation:  c.QueryParam("relation"),
+	}
+
+	tuples, err := h.relationService.ListTuples(orgID, filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "LIST_FAILED",
+				"message": "Failed to list relationship tuples",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": tuples,
+	})
+}
+
+func (h *RelationHandler) WriteTuples(c echo.Context) error {
+	var req models.WriteRelationTuplesRequest
+	if err := c.Bind(&req); err != nil {
+		return invalidRelationRequest(c)
+	}
+
+	actorID, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	written, deleted, err := h.relationService.WriteTuples(orgID, req, actorID, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return relationError(c, err, "WRITE_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"written": written,
+		"deleted": deleted,
+	})
+}
+
+func (h *RelationHandler) Check(c echo.Context) error {
+	var req models.RelationCheckRequest
+	if err := c.Bind(&req); err != nil {
+		return invalidRelationRequest(c)
+	}
+
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+	userID, _ := c.Get("user_id").(uuid.UUID)
+	if req.UserID != nil {
+		userID = *req.UserID
+	}
+
+	allowed, err := h.relationService.Check(orgID, userID, req.Namespace, req.ObjectID, req.Relation)
+	if err != nil {
+		return relationError(c, err, "CHECK_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"allowed": allowed,
+	})
+}
+
+func (h *RelationHandler) Expand(c echo.Context) error {
+	var req models.RelationExpandRequest
+	if err := c.Bind(&req); err != nil {
+		return invalidRelationRequest(c)
+	}
+
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	tree, err := h.relationService.Expand(orgID, req.Namespace, req.ObjectID, req.Relation)
+	if err != nil {
+		return relationError(c, err, "EXPAND_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, tree)
+}
+
+func (h *RelationHandler) ListObjects(c echo.Context) error {
+	var req models.RelationListObjectsRequest
+	if err := c.Bind(&req); err != nil {
+		return invalidRelationRequest(c)
+	}
+
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+	userID, _ := c.Get("user_id").(uuid.UUID)
+	if req.UserID != nil {
+		userID = *req.UserID
+	}
+
+	objects, err := h.relationService.ListObjects(orgID, userID, req.Namespace, req.Relation)
+	if err != nil {
+		return relationError(c, err, "LIST_OBJECTS_FAILED")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": objects,
+	})
+}
+
+func invalidRelationRequest(c echo.Context) error {
+	return c.JSON(http.StatusBadRequest, map[string]interface{}{
+		"error": map[string]string{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request body",
+		},
+	})
+}
+
+func relationError(c echo.Context, err error, fallbackCode string) error {
+	switch {
+	case errors.Is(err, services.ErrUnknownNamespace):
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNKNOWN_NAMESPACE",
+				"message": err.Error(),
+			},
+		})
+	case errors.Is(err, services.ErrUnknownRelation):
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNKNOWN_RELATION",
+				"message": err.Error(),
+			},
+		})
+	case errors.Is(err, services.ErrInvalidRelation):
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": err.Error(),
+			},
+		})
+	case errors.Is(err, services.ErrRelationGraphTooDeep):
+		return c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "RELATION_GRAPH_TOO_DEEP",
+				"message": err.Error(),
+			},
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+		"error": map[string]string{
+			"code":    fallbackCode,
+			"message": "Failed to evaluate relationships",
+		},
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/auth-service/internal/models"
 	"github.com/auth-service/internal/services"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -113,8 +114,16 @@ func (h *RoleHandler) UpdateRole(c echo.Context) error {
 		})
 	}
 
-	role, err := h.roleService.UpdateRole(id, req)
+	updatedBy, _ := c.Get("user_id").(uuid.UUID)
+
+	role, err := h.roleService.UpdateRole(id, req, updatedBy, c.RealIP(), c.Request().UserAgent())
 	if err != nil {
+		if err == services.ErrSystemRole {
+			return systemRoleResponse(c)
+		}
+		if err == services.ErrLastAdmin {
+			return lastAdminResponse(c)
+		}
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{
 			"error": map[string]string{
 				"code":    "UPDATE_FAILED",
@@ -138,7 +147,15 @@ func (h *RoleHandler) DeleteRole(c echo.Context) error {
 		})
 	}
 
-	if err := h.roleService.DeleteRole(id); err != nil {
+	deletedBy, _ := c.Get("user_id").(uuid.UUID)
+
+	if err := h.roleService.DeleteRole(id, deletedBy, c.RealIP(), c.Request().UserAgent()); err != nil {
+		if err == services.ErrSystemRole {
+			return systemRoleResponse(c)
+		}
+		if err == services.ErrLastAdmin {
+			return lastAdminResponse(c)
+		}
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{
 			"error": map[string]string{
 				"code":    "DELETE_FAILED",
@@ -151,3 +168,12 @@ func (h *RoleHandler) DeleteRole(c echo.Context) error {
 		"message": "Role deleted successfully",
 	})
 }
+
+func systemRoleResponse(c echo.Context) error {
+	return c.JSON(http.StatusConflict, map[string]interface{}{
+		"error": map[string]string{
+			"code":    "SYSTEM_ROLE",
+			"message": services.ErrSystemRole.Error(),
+		},
+	})
+}
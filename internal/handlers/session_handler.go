@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type SessionHandler struct {
+	sessionService *services.SessionService
+}
+
+func NewSessionHandler(sessionService *services.SessionService) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+func (h *SessionHandler) ListMySessions(c echo.Context) error {
+	userID, ok := c.Get("user_id").(uuid.UUID)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNAUTHORIZED",
+				"message": "User not authenticated",
+			},
+		})
+	}
+
+	currentSessionID, _ := c.Get("session_id").(uuid.UUID)
+
+	sessions, err := h.sessionService.ListSessions(userID, currentSessionID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "LIST_FAILED",
+				"message": "Failed to list sessions",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": sessions,
+	})
+}
+
+func (h *SessionHandler) RevokeMySession(c echo.Context) error {
+	userID, ok := c.Get("user_id").(uuid.UUID)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNAUTHORIZED",
+				"message": "User not authenticated",
+			},
+		})
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid session ID",
+			},
+		})
+	}
+
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+
+	if err := h.sessionService.RevokeSession(userID, sessionID, userID, ip, userAgent); err != nil {
+		if err == services.ErrSessionNotFound {
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "SESSION_NOT_FOUND",
+					"message": "Session not found",
+				},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "REVOKE_FAILED",
+				"message": "Failed to revoke session",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Session revoked successfully",
+	})
+}
+
+func (h *SessionHandler) ListUserSessions(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid user ID",
+			},
+		})
+	}
+
+	currentSessionID, _ := c.Get("session_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	sessions, err := h.sessionService.ListUserSessions(orgID, userID, currentSessionID)
+	if err != nil {
+		if err == services.ErrUserNotFound {
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "USER_NOT_FOUND",
+					"message": "User not found",
+				},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "LIST_FAILED",
+				"message": "Failed to list sessions",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": sessions,
+	})
+}
+
+func (h *SessionHandler) RevokeUserSession(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid user ID",
+			},
+		})
+	}
+
+	sessionID, err := uuid.Parse(c.Param("sid"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid session ID",
+			},
+		})
+	}
+
+	revokedBy, _ := c.Get("user_id").(uuid.UUID)
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	if err := h.sessionService.RevokeUserSession(orgID, userID, sessionID, revokedBy, ip, userAgent); err != nil {
+		if err == services.ErrSessionNotFound {
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "SESSION_NOT_FOUND",
+					"message": "Session not found",
+				},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "REVOKE_FAILED",
+				"message": "Failed to revoke session",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Session revoked successfully",
+	})
+}
+
+func (h *SessionHandler) RevokeAllUserSessions(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid user ID",
+			},
+		})
+	}
+
+	revokedBy, _ := c.Get("user_id").(uuid.UUID)
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	if err := h.sessionService.RevokeAllSessions(orgID, userID, revokedBy, ip, userAgent); err != nil {
+		if err == services.ErrUserNotFound {
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "USER_NOT_FOUND",
+					"message": "User not found",
+				},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "REVOKE_FAILED",
+				"message": "Failed to revoke sessions",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "All sessions revoked successfully",
+	})
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/auth-service/internal/models"
 	"github.com/auth-service/internal/services"
@@ -30,7 +32,8 @@ func (h *UserHandler) GetUser(c echo.Context) error {
 		})
 	}
 
-	user, err := h.userService.GetUser(id)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+	user, err := h.userService.GetUser(id, orgID)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]interface{}{
 			"error": map[string]string{
@@ -46,7 +49,15 @@ func (h *UserHandler) GetUser(c echo.Context) error {
 func (h *UserHandler) ListUsers(c echo.Context) error {
 	page, _ := strconv.Atoi(c.QueryParam("page"))
 	perPage, _ := strconv.Atoi(c.QueryParam("per_page"))
-	search := c.QueryParam("search")
+	filter := models.UserFilter{
+		Search:     c.QueryParam("search"),
+		Attributes: map[string]string{},
+	}
+	for key, values := range c.QueryParams() {
+		if attr := strings.TrimPrefix(key, "attr."); attr != key && len(values) > 0 {
+			filter.Attributes[attr] = values[0]
+		}
+	}
 
 	if page < 1 {
 		page = 1
@@ -55,8 +66,17 @@ func (h *UserHandler) ListUsers(c echo.Context) error {
 		perPage = 20
 	}
 
-	result, err := h.userService.ListUsers(page, perPage, search)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+	result, err := h.userService.ListUsers(orgID, page, perPage, filter)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidAttributeFilter) {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "INVALID_FILTER",
+					"message": err.Error(),
+				},
+			})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
 			"error": map[string]string{
 				"code":    "LIST_FAILED",
@@ -91,16 +111,15 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 	createdBy, _ := c.Get("user_id").(uuid.UUID)
 	ip := c.RealIP()
 	userAgent := c.Request().UserAgent()
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
 
-	user, err := h.userService.CreateUser(req, createdBy, ip, userAgent)
+	user, err := h.userService.CreateUser(req, orgID, createdBy, ip, userAgent)
 	if err != nil {
-		if err == services.ErrDuplicateEmail {
-			return c.JSON(http.StatusConflict, map[string]interface{}{
-				"error": map[string]string{
-					"code":    "DUPLICATE_EMAIL",
-					"message": "Email already exists",
-				},
-			})
+		if isDuplicateIdentifier(err) {
+			return duplicateIdentifierResponse(c, err)
+		}
+		if errors.Is(err, services.ErrRoleNotManageable) {
+			return roleNotManageableResponse(c, err)
 		}
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{
 			"error": map[string]string{
@@ -138,9 +157,19 @@ func (h *UserHandler) UpdateUser(c echo.Context) error {
 	updatedBy, _ := c.Get("user_id").(uuid.UUID)
 	ip := c.RealIP()
 	userAgent := c.Request().UserAgent()
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
 
-	user, err := h.userService.UpdateUser(id, req, updatedBy, ip, userAgent)
+	user, err := h.userService.UpdateUser(id, orgID, req, updatedBy, ip, userAgent)
 	if err != nil {
+		if err == services.ErrSharedAccount {
+			return sharedAccountResponse(c)
+		}
+		if err == services.ErrLastAdmin {
+			return lastAdminResponse(c)
+		}
+		if isDuplicateIdentifier(err) {
+			return duplicateIdentifierResponse(c, err)
+		}
 		if err == services.ErrUserNotFound {
 			return c.JSON(http.StatusNotFound, map[string]interface{}{
 				"error": map[string]string{
@@ -172,7 +201,15 @@ func (h *UserHandler) DeleteUser(c echo.Context) error {
 		})
 	}
 
-	if err := h.userService.DeleteUser(id); err != nil {
+	deletedBy, _ := c.Get("user_id").(uuid.UUID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+	if err := h.userService.DeleteUser(id, orgID, deletedBy, c.RealIP(), c.Request().UserAgent()); err != nil {
+		if err == services.ErrSharedAccount {
+			return sharedAccountResponse(c)
+		}
+		if err == services.ErrLastAdmin {
+			return lastAdminResponse(c)
+		}
 		if err == services.ErrUserNotFound {
 			return c.JSON(http.StatusNotFound, map[string]interface{}{
 				"error": map[string]string{
@@ -219,8 +256,12 @@ func (h *UserHandler) AssignRole(c echo.Context) error {
 	assignedBy, _ := c.Get("user_id").(uuid.UUID)
 	ip := c.RealIP()
 	userAgent := c.Request().UserAgent()
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
 
-	if err := h.userService.AssignRole(userID, req.RoleID, assignedBy, ip, userAgent); err != nil {
+	if err := h.userService.AssignRole(userID, orgID, req, assignedBy, ip, userAgent); err != nil {
+		if errors.Is(err, services.ErrRoleNotManageable) {
+			return roleNotManageableResponse(c, err)
+		}
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{
 			"error": map[string]string{
 				"code":    "ASSIGN_ROLE_FAILED",
@@ -260,8 +301,15 @@ func (h *UserHandler) UnassignRole(c echo.Context) error {
 	removedBy, _ := c.Get("user_id").(uuid.UUID)
 	ip := c.RealIP()
 	userAgent := c.Request().UserAgent()
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
 
-	if err := h.userService.UnassignRole(userID, roleID, removedBy, ip, userAgent); err != nil {
+	if err := h.userService.UnassignRole(userID, orgID, roleID, removedBy, ip, userAgent); err != nil {
+		if err == services.ErrLastAdmin {
+			return lastAdminResponse(c)
+		}
+		if errors.Is(err, services.ErrRoleNotManageable) {
+			return roleNotManageableResponse(c, err)
+		}
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{
 			"error": map[string]string{
 				"code":    "UNASSIGN_ROLE_FAILED",
@@ -275,6 +323,150 @@ func (h *UserHandler) UnassignRole(c echo.Context) error {
 	})
 }
 
+func (h *UserHandler) SuspendUser(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid user ID format",
+			},
+		})
+	}
+
+	var req models.SuspendUserRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	if req.Reason == "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "VALIDATION_ERROR",
+				"message": "Reason is required",
+			},
+		})
+	}
+
+	suspendedBy, _ := c.Get("user_id").(uuid.UUID)
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	user, err := h.userService.SuspendUser(id, orgID, req, suspendedBy, ip, userAgent)
+	if err != nil {
+		if err == services.ErrSharedAccount {
+			return sharedAccountResponse(c)
+		}
+		if err == services.ErrLastAdmin {
+			return lastAdminResponse(c)
+		}
+		if err == services.ErrUserNotFound {
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "USER_NOT_FOUND",
+					"message": "User not found",
+				},
+			})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "SUSPEND_FAILED",
+				"message": err.Error(),
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+func (h *UserHandler) UnsuspendUser(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid user ID format",
+			},
+		})
+	}
+
+	unsuspendedBy, _ := c.Get("user_id").(uuid.UUID)
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	if err := h.userService.UnsuspendUser(id, orgID, unsuspendedBy, ip, userAgent); err != nil {
+		if err == services.ErrSharedAccount {
+			return sharedAccountResponse(c)
+		}
+		if err == services.ErrUserNotFound {
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "USER_NOT_FOUND",
+					"message": "User not found",
+				},
+			})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNSUSPEND_FAILED",
+				"message": err.Error(),
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "User unsuspended successfully",
+	})
+}
+
+func (h *UserHandler) UnlockUser(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_ID",
+				"message": "Invalid user ID format",
+			},
+		})
+	}
+
+	unlockedBy, _ := c.Get("user_id").(uuid.UUID)
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+
+	if err := h.userService.UnlockUser(id, orgID, unlockedBy, ip, userAgent); err != nil {
+		if err == services.ErrSharedAccount {
+			return sharedAccountResponse(c)
+		}
+		if err == services.ErrUserNotFound {
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "USER_NOT_FOUND",
+					"message": "User not found",
+				},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNLOCK_FAILED",
+				"message": "Failed to unlock user",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "User unlocked successfully",
+	})
+}
+
 func (h *UserHandler) GetCurrentUser(c echo.Context) error {
 	userID, ok := c.Get("user_id").(uuid.UUID)
 	if !ok {
@@ -286,7 +478,8 @@ func (h *UserHandler) GetCurrentUser(c echo.Context) error {
 		})
 	}
 
-	user, err := h.userService.GetUser(userID)
+	orgID, _ := c.Get("organization_id").(uuid.UUID)
+	user, err := h.userService.GetUser(userID, orgID)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]interface{}{
 			"error": map[string]string{
@@ -299,6 +492,51 @@ func (h *UserHandler) GetCurrentUser(c echo.Context) error {
 	return c.JSON(http.StatusOK, user)
 }
 
+func (h *UserHandler) UpdateCurrentUserMetadata(c echo.Context) error {
+	userID, ok := c.Get("user_id").(uuid.UUID)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UNAUTHORIZED",
+				"message": "User not authenticated",
+			},
+		})
+	}
+
+	var attributes map[string]interface{}
+	if err := c.Bind(&attributes); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+
+	user, err := h.userService.UpdateOwnMetadata(userID, attributes, ip, userAgent)
+	if err != nil {
+		if err == services.ErrUserNotFound {
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "USER_NOT_FOUND",
+					"message": "User not found",
+				},
+			})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "UPDATE_METADATA_FAILED",
+				"message": err.Error(),
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, user.Metadata)
+}
+
 type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password"`
 	NewPassword string `json:"new_password"`
@@ -341,3 +579,32 @@ func (h *UserHandler) ChangePassword(c echo.Context) error {
 		"message": "Password changed successfully",
 	})
 }
+
+func lastAdminResponse(c echo.Context) error {
+	return c.JSON(http.StatusConflict, map[string]interface{}{
+		"error": map[string]string{
+			"code":    "LAST_ADMIN",
+			"message": services.ErrLastAdmin.Error(),
+		},
+	})
+}
+
+func sharedAccountResponse(c echo.Context) error {
+	return c.JSON(http.StatusForbidden, map[string]interface{}{
+		"error": map[string]string{
+			"code":    "SHARED_ACCOUNT",
+			"message": services.ErrSharedAccount.Error(),
+		},
+	})
+}
+
+// roleNotManageableResponse names the role the caller may not assign or
+// unassign.
+func roleNotManageableResponse(c echo.Context, err error) error {
+	return c.JSON(http.StatusForbidden, map[string]interface{}{
+		"error": map[string]string{
+			"code":    "ROLE_NOT_MANAGEABLE",
+			"message": err.Error(),
+		},
+	})
+}
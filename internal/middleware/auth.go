@@ -4,16 +4,27 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/services"
 	"github.com/auth-service/internal/utils"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
 type AuthMiddleware struct {
-	jwtManager *utils.JWTManager
+	jwtManager     *utils.JWTManager
+	denylist       *services.TokenDenylist
+	securityStamps *services.SecurityStamps
+	authzService   *services.AuthzService
 }
 
-func NewAuthMiddleware(jwtManager *utils.JWTManager) *AuthMiddleware {
-	return &AuthMiddleware{jwtManager: jwtManager}
+func NewAuthMiddleware(jwtManager *utils.JWTManager, denylist *services.TokenDenylist, securityStamps *services.SecurityStamps, authzService *services.AuthzService) *AuthMiddleware {
+	return &AuthMiddleware{
+		jwtManager:     jwtManager,
+		denylist:       denylist,
+		securityStamps: securityStamps,
+		authzService:   authzService,
+	}
 }
 
 func (m *AuthMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
@@ -48,9 +59,47 @@ func (m *AuthMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 			})
 		}
 
+		if m.denylist.IsRevoked(claims) {
+			return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "SESSION_REVOKED",
+					"message": "Session has been revoked",
+				},
+			})
+		}
+
+		// A changed stamp means roles or account status changed since the
+		// token was issued; the client has to refresh to pick them up.
+		stamp, err := m.securityStamps.Current(claims.UserID)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "INVALID_TOKEN",
+					"message": "Invalid or expired token",
+				},
+			})
+		}
+		if claims.SecurityStamp != stamp {
+			return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "TOKEN_STALE",
+					"message": "Token is out of date, refresh it to continue",
+				},
+			})
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
 		c.Set("roles", claims.Roles)
+		c.Set("session_id", claims.SessionID)
+		c.Set("token_id", claims.ID)
+
+		// Tokens issued before organizations existed act in the default one
+		orgID := claims.OrganizationID
+		if orgID == uuid.Nil {
+			orgID = models.DefaultOrganizationID
+		}
+		c.Set("organization_id", orgID)
 
 		return next(c)
 	}
@@ -86,3 +135,126 @@ func (m *AuthMiddleware) RequireRoles(roles ...string) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// RequirePermission allows the request only if the user holds every listed
+// permission. Permissions are resolved from the user's current roles rather
+// than the token, so grants made after login apply straight away.
+func (m *AuthMiddleware) RequirePermission(permissions ...string) echo.MiddlewareFunc {
+	return m.requirePermissionsIn(func(c echo.Context) uuid.UUID {
+		orgID, _ := c.Get("organization_id").(uuid.UUID)
+		return orgID
+	}, permissions)
+}
+
+// RequirePlatformPermission is RequirePermission for data shared by every
+// organization, such as role and permission definitions: the permissions
+// must be held in the default organization, whichever one the caller is
+// working in.
+func (m *AuthMiddleware) RequirePlatformPermission(permissions ...string) echo.MiddlewareFunc {
+	return m.requirePermissionsIn(func(c echo.Context) uuid.UUID {
+		return models.DefaultOrganizationID
+	}, permissions)
+}
+
+func (m *AuthMiddleware) requirePermissionsIn(orgOf func(c echo.Context) uuid.UUID, permissions []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, ok := c.Get("user_id").(uuid.UUID)
+			orgID := orgOf(c)
+			if !ok {
+				return c.JSON(http.StatusForbidden, map[string]interface{}{
+					"error": map[string]string{
+						"code":    "FORBIDDEN",
+						"message": "Access denied",
+					},
+				})
+			}
+
+			allowed, err := m.authzService.HasPermissions(userID, orgID, permissions...)
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+					"error": map[string]string{
+						"code":    "AUTHORIZATION_FAILED",
+						"message": "Failed to resolve permissions",
+					},
+				})
+			}
+
+			if !allowed {
+				return c.JSON(http.StatusForbidden, map[string]interface{}{
+					"error": map[string]string{
+						"code":    "FORBIDDEN",
+						"message": "Insufficient permissions",
+					},
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// ResourceResolver describes the resource a request acts on, for policies.
+type ResourceResolver func(c echo.Context) services.PolicyResource
+
+// ResourceParam builds a resolver for routes that name the resource in a
+// path parameter, e.g. ResourceParam("user", "id") for /users/:id.
+func ResourceParam(resourceType, param string) ResourceResolver {
+	return func(c echo.Context) services.PolicyResource {
+		return services.PolicyResource{Type: resourceType, ID: c.Param(param)}
+	}
+}
+
+// RequirePolicy authorizes the action through the policy engine, which falls
+// back to RequirePermission semantics when no policy applies.
+func (m *AuthMiddleware) RequirePolicy(action string, resource ResourceResolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, ok := c.Get("user_id").(uuid.UUID)
+			orgID, _ := c.Get("organization_id").(uuid.UUID)
+			if !ok {
+				return c.JSON(http.StatusForbidden, map[string]interface{}{
+					"error": map[string]string{
+						"code":    "FORBIDDEN",
+						"message": "Access denied",
+					},
+				})
+			}
+
+			req := services.AccessRequest{
+				UserID:         userID,
+				OrganizationID: orgID,
+				Action:         action,
+				IP:             c.RealIP(),
+			}
+			if resource != nil {
+				req.Resource = resource(c)
+			}
+
+			decision, err := m.authzService.Authorize(req)
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+					"error": map[string]string{
+						"code":    "AUTHORIZATION_FAILED",
+						"message": "Failed to evaluate access policies",
+					},
+				})
+			}
+
+			if !decision.Allowed {
+				message := "Insufficient permissions"
+				if decision.Policy != "" {
+					message = "Access denied by policy " + decision.Policy
+				}
+				return c.JSON(http.StatusForbidden, map[string]interface{}{
+					"error": map[string]string{
+						"code":    "FORBIDDEN",
+						"message": message,
+					},
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,6 +11,8 @@ import (
 type User struct {
 	ID               uuid.UUID  `json:"id"`
 	Email            string     `json:"email"`
+	Username         *string    `json:"username,omitempty"`
+	Phone            *string    `json:"phone,omitempty"`
 	PasswordHash     string     `json:"-"`
 	DisplayName      string     `json:"display_name"`
 	IsActive         bool       `json:"is_active"`
@@ -18,30 +22,147 @@ type User struct {
 	LastLoginAt      *time.Time `json:"last_login_at,omitempty"`
 	FailedLoginCount int        `json:"-"`
 	LockedUntil      *time.Time `json:"-"`
+
+	DeletionRequestedAt  *time.Time `json:"deletion_requested_at,omitempty"`
+	DeletionScheduledFor *time.Time `json:"deletion_scheduled_for,omitempty"`
+	ErasedAt             *time.Time `json:"erased_at,omitempty"`
+
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty"`
+	SuspensionReason *string    `json:"suspension_reason,omitempty"`
+	SuspendedBy      *uuid.UUID `json:"suspended_by,omitempty"`
+
+	DormancyWarnedAt *time.Time `json:"dormancy_warned_at,omitempty"`
+
+	Metadata UserMetadata `json:"metadata"`
+
+	// SecurityStamp changes whenever tokens issued to the user must be
+	// re-issued, e.g. after a role change or deactivation.
+	SecurityStamp string `json:"-"`
+}
+
+type UserMetadata struct {
+	User  map[string]interface{} `json:"user"`
+	Admin map[string]interface{} `json:"admin"`
+}
+
+func (u *User) IsSuspended(now time.Time) bool {
+	if u.SuspendedAt == nil {
+		return false
+	}
+	return u.SuspendedUntil == nil || now.Before(*u.SuspendedUntil)
+}
+
+// DefaultOrganizationID is the organization that existing users and role
+// assignments were migrated into, and that self-registered users join.
+var DefaultOrganizationID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+type Organization struct {
+	ID        uuid.UUID `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrganizationMembership struct {
+	Organization Organization `json:"organization"`
+	Roles        []Role       `json:"roles"`
+	JoinedAt     time.Time    `json:"joined_at"`
+}
+
+type Group struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	RoleIDs        []int     `json:"role_ids"`
+	MemberCount    int       `json:"member_count"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type GroupMember struct {
+	UserID      uuid.UUID  `json:"user_id"`
+	Email       string     `json:"email"`
+	DisplayName string     `json:"display_name"`
+	AddedBy     *uuid.UUID `json:"added_by,omitempty"`
+	AddedAt     time.Time  `json:"added_at"`
 }
 
 type Role struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	MaxSessions *int   `json:"max_sessions,omitempty"`
+	Inherits    []int  `json:"inherits,omitempty"`
+	Manages     []int  `json:"manages,omitempty"`
+	IsSystem    bool   `json:"is_system"`
+}
+
+type Permission struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
 
 type UserRole struct {
-	UserID     uuid.UUID `json:"user_id"`
-	RoleID     int       `json:"role_id"`
-	AssignedBy uuid.UUID `json:"assigned_by"`
-	AssignedAt time.Time `json:"assigned_at"`
+	UserID         uuid.UUID  `json:"user_id"`
+	OrganizationID uuid.UUID  `json:"organization_id"`
+	RoleID         int        `json:"role_id"`
+	AssignedBy     *uuid.UUID `json:"assigned_by,omitempty"`
+	AssignedAt     time.Time  `json:"assigned_at"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
 }
 
 type RefreshToken struct {
-	ID        uuid.UUID `json:"id"`
-	UserID    uuid.UUID `json:"user_id"`
-	TokenHash string    `json:"-"`
-	IssuedAt  time.Time `json:"issued_at"`
-	ExpiresAt time.Time `json:"expires_at"`
-	Revoked   bool      `json:"revoked"`
-	UserAgent string    `json:"user_agent"`
-	IPAddress string    `json:"ip_address"`
+	ID               uuid.UUID    `json:"id"`
+	UserID           uuid.UUID    `json:"user_id"`
+	SessionID        uuid.UUID    `json:"session_id"`
+	OrganizationID   uuid.UUID    `json:"organization_id"`
+	TokenHash        string       `json:"-"`
+	IssuedAt         time.Time    `json:"issued_at"`
+	SessionStartedAt time.Time    `json:"session_started_at"`
+	ExpiresAt        time.Time    `json:"expires_at"`
+	Revoked          bool         `json:"revoked"`
+	UserAgent        string       `json:"user_agent"`
+	IPAddress        string       `json:"ip_address"`
+	Geo              *GeoLocation `json:"geo,omitempty"`
+}
+
+type GeoLocation struct {
+	Country   string  `json:"country,omitempty"`
+	City      string  `json:"city,omitempty"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+	ASN       uint    `json:"asn,omitempty"`
+	ASOrg     string  `json:"as_org,omitempty"`
+}
+
+func (g *GeoLocation) HasCoordinates() bool {
+	return g != nil && (g.Latitude != 0 || g.Longitude != 0)
+}
+
+type DeviceInfo struct {
+	Browser        string `json:"browser"`
+	BrowserVersion string `json:"browser_version"`
+	OS             string `json:"os"`
+	Platform       string `json:"platform"`
+	Mobile         bool   `json:"mobile"`
+	Bot            bool   `json:"bot"`
+}
+
+type Session struct {
+	ID             uuid.UUID    `json:"id"`
+	OrganizationID uuid.UUID    `json:"organization_id"`
+	Device         DeviceInfo   `json:"device"`
+	UserAgent      string       `json:"user_agent"`
+	IPAddress      string       `json:"ip_address"`
+	Location       *GeoLocation `json:"location,omitempty"`
+	StartedAt      time.Time    `json:"started_at"`
+	LastActiveAt   time.Time    `json:"last_active_at"`
+	ExpiresAt      time.Time    `json:"expires_at"`
+	Current        bool         `json:"current"`
 }
 
 type EmailTokenType string
@@ -49,6 +170,8 @@ type EmailTokenType string
 const (
 	EmailTokenTypeVerify EmailTokenType = "verify"
 	EmailTokenTypeReset  EmailTokenType = "reset"
+	EmailTokenTypeNotMe  EmailTokenType = "not_me"
+	EmailTokenTypeStepUp EmailTokenType = "step_up"
 )
 
 type EmailToken struct {
@@ -58,6 +181,19 @@ type EmailToken struct {
 	Type      EmailTokenType `json:"type"`
 	ExpiresAt time.Time      `json:"expires_at"`
 	Used      bool           `json:"used"`
+	SessionID *uuid.UUID     `json:"session_id,omitempty"`
+}
+
+type KnownDevice struct {
+	ID           uuid.UUID `json:"id"`
+	UserID       uuid.UUID `json:"user_id"`
+	Fingerprint  string    `json:"fingerprint"`
+	Browser      string    `json:"browser"`
+	OS           string    `json:"os"`
+	IPPrefix     string    `json:"ip_prefix"`
+	FirstSession uuid.UUID `json:"first_session_id"`
+	FirstSeenAt  time.Time `json:"first_seen_at"`
+	LastSeenAt   time.Time `json:"last_seen_at"`
 }
 
 type AuditEventType string
@@ -71,8 +207,180 @@ const (
 	AuditEventRegister       AuditEventType = "register"
 	AuditEventEmailVerified  AuditEventType = "email_verified"
 	AuditEventPasswordReset  AuditEventType = "password_reset"
+	AuditEventDataExport     AuditEventType = "data_export"
+
+	AuditEventDeletionRequested AuditEventType = "account_deletion_requested"
+	AuditEventDeletionCancelled AuditEventType = "account_deletion_cancelled"
+	AuditEventAccountErased     AuditEventType = "account_erased"
+
+	AuditEventInvitationCreated  AuditEventType = "invitation_created"
+	AuditEventInvitationResent   AuditEventType = "invitation_resent"
+	AuditEventInvitationRevoked  AuditEventType = "invitation_revoked"
+	AuditEventInvitationAccepted AuditEventType = "invitation_accepted"
+
+	AuditEventAccountSuspended   AuditEventType = "account_suspended"
+	AuditEventAccountUnsuspended AuditEventType = "account_unsuspended"
+	AuditEventAccountUnlocked    AuditEventType = "account_unlocked"
+
+	AuditEventDormancyWarning    AuditEventType = "dormancy_warning"
+	AuditEventAccountDeactivated AuditEventType = "account_deactivated"
+	AuditEventMetadataUpdated    AuditEventType = "metadata_updated"
+
+	AuditEventConsentPublished AuditEventType = "consent_document_published"
+	AuditEventConsentAccepted  AuditEventType = "consent_accepted"
+
+	AuditEventSessionRevoked   AuditEventType = "session_revoked"
+	AuditEventNewDeviceLogin   AuditEventType = "new_device_login"
+	AuditEventLoginDisowned    AuditEventType = "login_disowned"
+	AuditEventSessionEvicted   AuditEventType = "session_evicted"
+	AuditEventImpossibleTravel AuditEventType = "impossible_travel"
+
+	AuditEventPermissionChange AuditEventType = "permission_change"
+
+	AuditEventOrganizationCreated       AuditEventType = "organization_created"
+	AuditEventOrganizationMemberAdded   AuditEventType = "organization_member_added"
+	AuditEventOrganizationMemberRemoved AuditEventType = "organization_member_removed"
+	AuditEventOrganizationSwitched      AuditEventType = "organization_switched"
+
+	AuditEventGroupChange        AuditEventType = "group_change"
+	AuditEventGroupMemberAdded   AuditEventType = "group_member_added"
+	AuditEventGroupMemberRemoved AuditEventType = "group_member_removed"
+
+	AuditEventAuthzDecision AuditEventType = "authz_decision"
+
+	AuditEventRelationChange AuditEventType = "relation_change"
+
+	AuditEventLastAdminProtected AuditEventType = "last_admin_protected"
+
+	AuditEventElevationRequested AuditEventType = "elevation_requested"
+	AuditEventElevationApproved  AuditEventType = "elevation_approved"
+	AuditEventElevationDenied    AuditEventType = "elevation_denied"
+	AuditEventElevationCancelled AuditEventType = "elevation_cancelled"
 )
 
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "pending"
+	InvitationStatusExpired  InvitationStatus = "expired"
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	InvitationStatusRevoked  InvitationStatus = "revoked"
+)
+
+type Invitation struct {
+	ID             uuid.UUID        `json:"id"`
+	OrganizationID uuid.UUID        `json:"organization_id"`
+	Email          string           `json:"email"`
+	DisplayName    string           `json:"display_name,omitempty"`
+	RoleIDs        []int            `json:"role_ids"`
+	TokenHash      string           `json:"-"`
+	InvitedBy      *uuid.UUID       `json:"invited_by,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	ExpiresAt      time.Time        `json:"expires_at"`
+	AcceptedAt     *time.Time       `json:"accepted_at,omitempty"`
+	RevokedAt      *time.Time       `json:"revoked_at,omitempty"`
+	UserID         *uuid.UUID       `json:"user_id,omitempty"`
+	Status         InvitationStatus `json:"status"`
+}
+
+func (i *Invitation) StatusAt(now time.Time) InvitationStatus {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationStatusAccepted
+	case i.RevokedAt != nil:
+		return InvitationStatusRevoked
+	case now.After(i.ExpiresAt):
+		return InvitationStatusExpired
+	default:
+		return InvitationStatusPending
+	}
+}
+
+type ElevationStatus string
+
+const (
+	ElevationStatusPending   ElevationStatus = "pending"
+	ElevationStatusExpired   ElevationStatus = "expired"
+	ElevationStatusDenied    ElevationStatus = "denied"
+	ElevationStatusCancelled ElevationStatus = "cancelled"
+	ElevationStatusActive    ElevationStatus = "active"
+	ElevationStatusEnded     ElevationStatus = "ended"
+)
+
+// ElevationRequest asks for a role for a limited time. An approved request
+// grants the role until GrantExpiresAt; a request nobody decides on lapses
+// at ExpiresAt.
+type ElevationRequest struct {
+	ID              uuid.UUID       `json:"id"`
+	OrganizationID  uuid.UUID       `json:"organization_id"`
+	UserID          uuid.UUID       `json:"user_id"`
+	RoleID          int             `json:"role_id"`
+	DurationMinutes int             `json:"duration_minutes"`
+	Justification   string          `json:"justification"`
+	CreatedAt       time.Time       `json:"created_at"`
+	ExpiresAt       time.Time       `json:"expires_at"`
+	DecidedBy       *uuid.UUID      `json:"decided_by,omitempty"`
+	DecisionNote    string          `json:"decision_note,omitempty"`
+	ApprovedAt      *time.Time      `json:"approved_at,omitempty"`
+	DeniedAt        *time.Time      `json:"denied_at,omitempty"`
+	CancelledAt     *time.Time      `json:"cancelled_at,omitempty"`
+	GrantExpiresAt  *time.Time      `json:"grant_expires_at,omitempty"`
+	Status          ElevationStatus `json:"status"`
+}
+
+func (e *ElevationRequest) StatusAt(now time.Time) ElevationStatus {
+	switch {
+	case e.CancelledAt != nil:
+		return ElevationStatusCancelled
+	case e.DeniedAt != nil:
+		return ElevationStatusDenied
+	case e.ApprovedAt != nil && e.GrantExpiresAt != nil && now.Before(*e.GrantExpiresAt):
+		return ElevationStatusActive
+	case e.ApprovedAt != nil:
+		return ElevationStatusEnded
+	case now.After(e.ExpiresAt):
+		return ElevationStatusExpired
+	default:
+		return ElevationStatusPending
+	}
+}
+
+type ConsentDocumentType string
+
+const (
+	ConsentDocumentTerms   ConsentDocumentType = "terms"
+	ConsentDocumentPrivacy ConsentDocumentType = "privacy"
+)
+
+type ConsentDocument struct {
+	ID          int                 `json:"id"`
+	Type        ConsentDocumentType `json:"type"`
+	Version     string              `json:"version"`
+	URL         string              `json:"url,omitempty"`
+	PublishedAt time.Time           `json:"published_at"`
+	PublishedBy *uuid.UUID          `json:"published_by,omitempty"`
+}
+
+type UserConsent struct {
+	UserID       uuid.UUID           `json:"user_id"`
+	DocumentID   int                 `json:"document_id"`
+	DocumentType ConsentDocumentType `json:"document_type"`
+	Version      string              `json:"version"`
+	AcceptedAt   time.Time           `json:"accepted_at"`
+	IPAddress    string              `json:"ip_address"`
+	UserAgent    string              `json:"user_agent"`
+}
+
+type ConsentReportEntry struct {
+	UserID       uuid.UUID           `json:"user_id"`
+	Email        string              `json:"email"`
+	DisplayName  string              `json:"display_name"`
+	DocumentID   int                 `json:"document_id"`
+	DocumentType ConsentDocumentType `json:"document_type"`
+	Version      string              `json:"version"`
+	AcceptedAt   time.Time           `json:"accepted_at"`
+}
+
 type AuditEvent struct {
 	ID        uuid.UUID      `json:"id"`
 	UserID    *uuid.UUID     `json:"user_id,omitempty"`
@@ -80,24 +388,41 @@ type AuditEvent struct {
 	Payload   string         `json:"payload"`
 	IPAddress string         `json:"ip_address"`
 	UserAgent string         `json:"user_agent"`
+	Geo       *GeoLocation   `json:"geo,omitempty"`
 	CreatedAt time.Time      `json:"created_at"`
 }
 
 type RegisterRequest struct {
 	Email       string `json:"email"`
+	Username    string `json:"username,omitempty"`
+	Phone       string `json:"phone,omitempty"`
 	Password    string `json:"password"`
 	DisplayName string `json:"display_name"`
+
+	TermsVersion   string `json:"terms_version"`
+	PrivacyVersion string `json:"privacy_version"`
 }
 
+// LoginRequest accepts an email, username or E.164 phone number in
+// Identifier. Email is still honoured for older clients. OrganizationID
+// picks the organization to act in; when empty the user's default
+// organization is used.
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Identifier     string    `json:"identifier"`
+	Email          string    `json:"email"`
+	Password       string    `json:"password"`
+	StepUpCode     string    `json:"step_up_code"`
+	OrganizationID uuid.UUID `json:"organization_id"`
 }
 
 type VerifyEmailRequest struct {
 	Token string `json:"token"`
 }
 
+type NotMeRequest struct {
+	Token string `json:"token" form:"token" query:"token"`
+}
+
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
@@ -112,9 +437,13 @@ type ResetPasswordRequest struct {
 }
 
 type AuthResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	ExpiresIn    int64  `json:"expires_in"`
+	AccessToken    string    `json:"access_token"`
+	RefreshToken   string    `json:"refresh_token,omitempty"`
+	ExpiresIn      int64     `json:"expires_in"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+
+	ConsentRequired bool              `json:"consent_required,omitempty"`
+	PendingConsents []ConsentDocument `json:"pending_consents,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -125,30 +454,261 @@ type ErrorResponse struct {
 }
 
 type UserWithRoles struct {
-	User  User   `json:"user"`
-	Roles []Role `json:"roles"`
+	User   User       `json:"user"`
+	Roles  []Role     `json:"roles"`
+	Grants []UserRole `json:"grants"`
 }
 
 type CreateUserRequest struct {
 	Email       string `json:"email"`
+	Username    string `json:"username,omitempty"`
+	Phone       string `json:"phone,omitempty"`
 	Password    string `json:"password"`
 	DisplayName string `json:"display_name"`
 	RoleIDs     []int  `json:"role_ids,omitempty"`
 }
 
+type CreateInvitationRequest struct {
+	Email       string `json:"email"`
+	DisplayName string `json:"display_name"`
+	RoleIDs     []int  `json:"role_ids,omitempty"`
+}
+
+type AcceptInvitationRequest struct {
+	Token       string `json:"token"`
+	Password    string `json:"password"`
+	DisplayName string `json:"display_name"`
+}
+
 type UpdateUserRequest struct {
-	Email       *string `json:"email,omitempty"`
-	DisplayName *string `json:"display_name,omitempty"`
-	IsActive    *bool   `json:"is_active,omitempty"`
+	Email       *string              `json:"email,omitempty"`
+	Username    *string              `json:"username,omitempty"`
+	Phone       *string              `json:"phone,omitempty"`
+	DisplayName *string              `json:"display_name,omitempty"`
+	IsActive    *bool                `json:"is_active,omitempty"`
+	Metadata    *UpdateMetadataInput `json:"metadata,omitempty"`
+}
+
+type UpdateMetadataInput struct {
+	User  map[string]interface{} `json:"user,omitempty"`
+	Admin map[string]interface{} `json:"admin,omitempty"`
+}
+
+type UserFilter struct {
+	OrganizationID uuid.UUID
+	Search         string
+	Attributes     map[string]string
+}
+
+type SuspendUserRequest struct {
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until,omitempty"`
 }
 
 type CreateRoleRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	MaxSessions *int   `json:"max_sessions"`
+	Inherits    []int  `json:"inherits"`
+	Manages     []int  `json:"manages"`
+}
+
+type CreatePermissionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type GrantPermissionRequest struct {
+	Permission string `json:"permission"`
 }
 
+// AssignRoleRequest grants a role, optionally only between StartsAt and
+// ExpiresAt. Assigning a role the user already has replaces its window.
 type AssignRoleRequest struct {
-	RoleID int `json:"role_id"`
+	RoleID    int        `json:"role_id"`
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type CreateElevationRequest struct {
+	RoleID          int    `json:"role_id"`
+	DurationMinutes int    `json:"duration_minutes"`
+	Justification   string `json:"justification"`
+}
+
+type ElevationDecisionRequest struct {
+	Note string `json:"note"`
+}
+
+type CreateOrganizationRequest struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+type AddOrganizationMemberRequest struct {
+	UserID  uuid.UUID `json:"user_id"`
+	RoleIDs []int     `json:"role_ids,omitempty"`
+}
+
+type JoinOrganizationRequest struct {
+	Token string `json:"token"`
+}
+
+type CreateGroupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	RoleIDs     []int  `json:"role_ids,omitempty"`
+}
+
+type UpdateGroupRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+type AddGroupMembersRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids"`
+}
+
+// RelationSubject is the subject of a relationship tuple, written as
+// "user:<id>", "group:<id>#member", "role:<name>#member", an object such as
+// "folder:<id>", or the userset "folder:<id>#viewer".
+type RelationSubject struct {
+	Namespace string
+	ID        string
+	Relation  string
+}
+
+func (s RelationSubject) String() string {
+	if s.Relation == "" {
+		return s.Namespace + ":" + s.ID
+	}
+	return s.Namespace + ":" + s.ID + "#" + s.Relation
+}
+
+func (s RelationSubject) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *RelationSubject) UnmarshalText(text []byte) error {
+	namespace, rest, ok := strings.Cut(string(text), ":")
+	if !ok || namespace == "" || rest == "" {
+		return errors.New("subject must look like namespace:id or namespace:id#relation")
+	}
+	id, relation, _ := strings.Cut(rest, "#")
+	*s = RelationSubject{Namespace: namespace, ID: id, Relation: relation}
+	return nil
+}
+
+type RelationTuple struct {
+	Namespace string          `json:"namespace"`
+	ObjectID  string          `json:"object_id"`
+	Relation  string          `json:"relation"`
+	Subject   RelationSubject `json:"subject"`
+	CreatedBy *uuid.UUID      `json:"created_by,omitempty"`
+	CreatedAt *time.Time      `json:"created_at,omitempty"`
+}
+
+type RelationTupleFilter struct {
+	Namespace string
+	ObjectID  string
+	Relation  string
+}
+
+type WriteRelationTuplesRequest struct {
+	Writes  []RelationTuple `json:"writes"`
+	Deletes []RelationTuple `json:"deletes"`
+}
+
+// RelationCheckRequest asks whether a user has a relation to an object.
+// UserID defaults to the caller.
+type RelationCheckRequest struct {
+	Namespace string     `json:"namespace"`
+	ObjectID  string     `json:"object_id"`
+	Relation  string     `json:"relation"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+}
+
+type RelationExpandRequest struct {
+	Namespace string `json:"namespace"`
+	ObjectID  string `json:"object_id"`
+	Relation  string `json:"relation"`
+}
+
+type RelationListObjectsRequest struct {
+	Namespace string     `json:"namespace"`
+	Relation  string     `json:"relation"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+}
+
+// RelationTree shows how a userset is made up. "union" nodes combine their
+// children; "direct" leaves list the subjects of stored tuples; and
+// "tuple_to_userset" nodes follow each object related through Userset.
+type RelationTree struct {
+	Kind     string            `json:"kind"`
+	Userset  string            `json:"userset"`
+	Subjects []RelationSubject `json:"subjects,omitempty"`
+	Children []RelationTree    `json:"children,omitempty"`
+}
+
+// AuthzSubject names who is asking: either a user ID (in OrganizationID, or
+// the default organization) or an access token issued by this service.
+type AuthzSubject struct {
+	UserID         *uuid.UUID `json:"user_id,omitempty"`
+	Token          string     `json:"token,omitempty"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+}
+
+type AuthzResource struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+type AuthzCheckRequest struct {
+	Subject  AuthzSubject  `json:"subject"`
+	Action   string        `json:"action"`
+	Resource AuthzResource `json:"resource"`
+	IP       string        `json:"ip,omitempty"`
+	Audit    bool          `json:"audit,omitempty"`
+}
+
+type AuthzBatchCheckRequest struct {
+	Checks []AuthzCheckRequest `json:"checks"`
+	Audit  bool                `json:"audit,omitempty"`
+}
+
+type AuthzDecision struct {
+	Allowed bool       `json:"allowed"`
+	Reason  string     `json:"reason"`
+	Policy  string     `json:"policy,omitempty"`
+	UserID  *uuid.UUID `json:"user_id,omitempty"`
+}
+
+type SwitchOrganizationRequest struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+type UserDataExport struct {
+	ExportedAt    time.Time                `json:"exported_at"`
+	User          User                     `json:"user"`
+	Organizations []OrganizationMembership `json:"organizations"`
+	Sessions      []RefreshToken           `json:"sessions"`
+	Consents      []UserConsent            `json:"consents"`
+	AuditEvents   []AuditEvent             `json:"audit_events"`
+}
+
+type PublishConsentDocumentRequest struct {
+	Type    ConsentDocumentType `json:"type"`
+	Version string              `json:"version"`
+	URL     string              `json:"url"`
+}
+
+type AcceptConsentsRequest struct {
+	DocumentIDs []int `json:"document_ids"`
 }
 
 type PaginationQuery struct {
@@ -18,14 +18,27 @@ func NewAuditRepository(db *sql.DB) *AuditRepository {
 
 func (r *AuditRepository) Create(event *models.AuditEvent) error {
 	query := `
-		INSERT INTO audit_events (id, user_id, event_type, payload, ip_address, user_agent, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO audit_events (id, user_id, event_type, payload, ip_address, user_agent, created_at, ` + geoColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
-	_, err := r.db.Exec(query, event.ID, event.UserID, event.EventType,
-		event.Payload, event.IPAddress, event.UserAgent, event.CreatedAt)
+	args := []interface{}{event.ID, event.UserID, event.EventType,
+		event.Payload, event.IPAddress, event.UserAgent, event.CreatedAt}
+	_, err := r.db.Exec(query, append(args, geoValues(event.Geo)...)...)
 	return err
 }
 
+func scanAuditEvent(row rowScanner) (*models.AuditEvent, error) {
+	event := &models.AuditEvent{}
+	var geo nullGeo
+	dest := append([]interface{}{&event.ID, &event.UserID, &event.EventType,
+		&event.Payload, &event.IPAddress, &event.UserAgent, &event.CreatedAt}, geo.dest()...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	event.Geo = geo.location()
+	return event, nil
+}
+
 func (r *AuditRepository) List(query models.AuditQuery) ([]models.AuditEvent, int64, error) {
 	if query.Page < 1 {
 		query.Page = 1
@@ -70,7 +83,7 @@ func (r *AuditRepository) List(query models.AuditQuery) ([]models.AuditEvent, in
 	countSQL := "SELECT COUNT(*) " + baseQuery
 	r.db.QueryRow(countSQL, args...).Scan(&total)
 
-	selectSQL := "SELECT id, user_id, event_type, payload, ip_address, user_agent, created_at " +
+	selectSQL := "SELECT id, user_id, event_type, payload, ip_address, user_agent, created_at, " + geoColumns + " " +
 		baseQuery + " ORDER BY created_at DESC LIMIT $" + string(rune('0'+argCount+1)) +
 		" OFFSET $" + string(rune('0'+argCount+2))
 	args = append(args, query.PerPage, offset)
@@ -83,13 +96,58 @@ func (r *AuditRepository) List(query models.AuditQuery) ([]models.AuditEvent, in
 
 	var events []models.AuditEvent
 	for rows.Next() {
-		var event models.AuditEvent
-		if err := rows.Scan(&event.ID, &event.UserID, &event.EventType,
-			&event.Payload, &event.IPAddress, &event.UserAgent, &event.CreatedAt); err != nil {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
 			return nil, 0, err
 		}
-		events = append(events, event)
+		events = append(events, *event)
 	}
 
 	return events, total, nil
 }
+
+func (r *AuditRepository) ListByUser(userID uuid.UUID) ([]models.AuditEvent, error) {
+	query := `
+		SELECT id, user_id, event_type, payload, ip_address, user_agent, created_at, ` + geoColumns + `
+		FROM audit_events WHERE user_id = $1 ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.AuditEvent
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+	return events, nil
+}
+
+func (r *AuditRepository) AnonymizeUserEvents(userID uuid.UUID) error {
+	query := `
+		UPDATE audit_events SET ip_address = '', user_agent = '',
+			   geo_country = NULL, geo_city = NULL, geo_latitude = NULL, geo_longitude = NULL,
+			   geo_asn = NULL, geo_as_org = NULL,
+			   payload = CASE WHEN jsonb_typeof(payload) = 'object' THEN payload - 'email' ELSE payload END
+		WHERE user_id = $1
+	`
+	_, err := r.db.Exec(query, userID)
+	return err
+}
+
+// LastLoginLocation returns the most recent successful login of the user
+// that could be placed on a map.
+func (r *AuditRepository) LastLoginLocation(userID uuid.UUID) (*models.AuditEvent, error) {
+	query := `
+		SELECT id, user_id, event_type, payload, ip_address, user_agent, created_at, ` + geoColumns + `
+		FROM audit_events
+		WHERE user_id = $1 AND event_type = $2 AND geo_latitude IS NOT NULL
+		ORDER BY created_at DESC LIMIT 1
+	`
+	return scanAuditEvent(r.db.QueryRow(query, userID, models.AuditEventLoginSuccess))
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+type ConsentRepository struct {
+	db *sql.DB
+}
+
+func NewConsentRepository(db *sql.DB) *ConsentRepository {
+	return &ConsentRepository{db: db}
+}
+
+func scanConsentDocument(row rowScanner) (*models.ConsentDocument, error) {
+	doc := &models.ConsentDocument{}
+	var url sql.NullString
+	if err := row.Scan(&doc.ID, &doc.Type, &doc.Version, &url, &doc.PublishedAt, &doc.PublishedBy); err != nil {
+		return nil, err
+	}
+	doc.URL = url.String
+	return doc, nil
+}
+
+func (r *ConsentRepository) listDocuments(query string, args ...interface{}) ([]models.ConsentDocument, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []models.ConsentDocument
+	for rows.Next() {
+		doc, err := scanConsentDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, *doc)
+	}
+	return docs, nil
+}
+
+func (r *ConsentRepository) CreateDocument(doc *models.ConsentDocument) error {
+	query := `
+		INSERT INTO consent_documents (type, version, url, published_at, published_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	return r.db.QueryRow(query, doc.Type, doc.Version, doc.URL, doc.PublishedAt, doc.PublishedBy).Scan(&doc.ID)
+}
+
+func (r *ConsentRepository) GetDocumentByID(id int) (*models.ConsentDocument, error) {
+	query := `SELECT id, type, version, url, published_at, published_by FROM consent_documents WHERE id = $1`
+	return scanConsentDocument(r.db.QueryRow(query, id))
+}
+
+func (r *ConsentRepository) GetDocumentByVersion(docType models.ConsentDocumentType, version string) (*models.ConsentDocument, error) {
+	query := `
+		SELECT id, type, version, url, published_at, published_by
+		FROM consent_documents WHERE type = $1 AND version = $2
+	`
+	return scanConsentDocument(r.db.QueryRow(query, docType, version))
+}
+
+func (r *ConsentRepository) ListDocuments() ([]models.ConsentDocument, error) {
+	query := `
+		SELECT id, type, version, url, published_at, published_by
+		FROM consent_documents ORDER BY type, published_at DESC
+	`
+	return r.listDocuments(query)
+}
+
+// ListCurrentDocuments returns the most recently published document of each type.
+func (r *ConsentRepository) ListCurrentDocuments() ([]models.ConsentDocument, error) {
+	query := `
+		SELECT DISTINCT ON (type) id, type, version, url, published_at, published_by
+		FROM consent_documents ORDER BY type, published_at DESC, id DESC
+	`
+	return r.listDocuments(query)
+}
+
+func (r *ConsentRepository) ListPendingForUser(userID uuid.UUID) ([]models.ConsentDocument, error) {
+	query := `
+		SELECT id, type, version, url, published_at, published_by FROM (
+			SELECT DISTINCT ON (type) id, type, version, url, published_at, published_by
+			FROM consent_documents ORDER BY type, published_at DESC, id DESC
+		) current
+		WHERE NOT EXISTS (
+			SELECT 1 FROM user_consents uc WHERE uc.user_id = $1 AND uc.document_id = current.id
+		)
+		ORDER BY type
+	`
+	return r.listDocuments(query, userID)
+}
+
+func (r *ConsentRepository) RecordConsent(userID uuid.UUID, documentID int, ip, userAgent string) error {
+	query := `
+		INSERT INTO user_consents (user_id, document_id, accepted_at, ip_address, user_agent)
+		VALUES ($1, $2, NOW(), $3, $4)
+		ON CONFLICT (user_id, document_id) DO NOTHING
+	`
+	_, err := r.db.Exec(query, userID, documentID, ip, userAgent)
+	return err
+}
+
+func (r *ConsentRepository) ListUserConsents(userID uuid.UUID) ([]models.UserConsent, error) {
+	query := `
+		SELECT uc.user_id, uc.document_id, cd.type, cd.version, uc.accepted_at,
+			   COALESCE(uc.ip_address, ''), COALESCE(uc.user_agent, '')
+		FROM user_consents uc
+		INNER JOIN consent_documents cd ON cd.id = uc.document_id
+		WHERE uc.user_id = $1
+		ORDER BY uc.accepted_at DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var consents []models.UserConsent
+	for rows.Next() {
+		var consent models.UserConsent
+		if err := rows.Scan(&consent.UserID, &consent.DocumentID, &consent.DocumentType, &consent.Version,
+			&consent.AcceptedAt, &consent.IPAddress, &consent.UserAgent); err != nil {
+			return nil, err
+		}
+		consents = append(consents, consent)
+	}
+	return consents, nil
+}
+
+func (r *ConsentRepository) Report(documentID, page, perPage int) ([]models.ConsentReportEntry, int64, error) {
+	offset := (page - 1) * perPage
+
+	where := ""
+	args := []interface{}{}
+	if documentID > 0 {
+		where = " WHERE uc.document_id = $1"
+		args = append(args, documentID)
+	}
+
+	var total int64
+	countQuery := "SELECT COUNT(*) FROM user_consents uc" + where
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT u.id, u.email, u.display_name, cd.id, cd.type, cd.version, uc.accepted_at
+		FROM user_consents uc
+		INNER JOIN users u ON u.id = uc.user_id
+		INNER JOIN consent_documents cd ON cd.id = uc.document_id` + where
+	if documentID > 0 {
+		query += " ORDER BY uc.accepted_at DESC LIMIT $2 OFFSET $3"
+	} else {
+		query += " ORDER BY uc.accepted_at DESC LIMIT $1 OFFSET $2"
+	}
+	args = append(args, perPage, offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var entries []models.ConsentReportEntry
+	for rows.Next() {
+		var entry models.ConsentReportEntry
+		if err := rows.Scan(&entry.UserID, &entry.Email, &entry.DisplayName, &entry.DocumentID,
+			&entry.DocumentType, &entry.Version, &entry.AcceptedAt); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, nil
+}
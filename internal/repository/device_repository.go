@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+type DeviceRepository struct {
+	db *sql.DB
+}
+
+func NewDeviceRepository(db *sql.DB) *DeviceRepository {
+	return &DeviceRepository{db: db}
+}
+
+func (r *DeviceRepository) IsKnown(userID uuid.UUID, fingerprint string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM known_devices WHERE user_id = $1 AND fingerprint = $2)`
+	var exists bool
+	err := r.db.QueryRow(query, userID, fingerprint).Scan(&exists)
+	return exists, err
+}
+
+func (r *DeviceRepository) CountForUser(userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM known_devices WHERE user_id = $1", userID).Scan(&count)
+	return count, err
+}
+
+// Upsert records the device, refreshing last_seen_at if it is already known.
+func (r *DeviceRepository) Upsert(device *models.KnownDevice) error {
+	query := `
+		INSERT INTO known_devices (id, user_id, fingerprint, browser, os, ip_prefix, first_session_id, first_seen_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (user_id, fingerprint) DO UPDATE SET last_seen_at = EXCLUDED.last_seen_at
+	`
+	_, err := r.db.Exec(query, device.ID, device.UserID, device.Fingerprint,
+		device.Browser, device.OS, device.IPPrefix, device.FirstSession, time.Now())
+	return err
+}
+
+// ForgetBySession drops the device that was first seen in the given session.
+func (r *DeviceRepository) ForgetBySession(userID, sessionID uuid.UUID) error {
+	_, err := r.db.Exec("DELETE FROM known_devices WHERE user_id = $1 AND first_session_id = $2", userID, sessionID)
+	return err
+}
+
+func (r *DeviceRepository) DeleteAllForUser(userID uuid.UUID) error {
+	_, err := r.db.Exec("DELETE FROM known_devices WHERE user_id = $1", userID)
+	return err
+}
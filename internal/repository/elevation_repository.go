@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+const elevationColumns = `id, organization_id, user_id, role_id, duration_minutes, justification, created_at, expires_at,
+	decided_by, decision_note, approved_at, denied_at, cancelled_at, grant_expires_at`
+
+// elevationPending matches requests nobody has decided on that have not
+// lapsed yet.
+const elevationPending = `approved_at IS NULL AND denied_at IS NULL AND cancelled_at IS NULL AND expires_at > NOW()`
+
+type ElevationRepository struct {
+	db *sql.DB
+}
+
+func NewElevationRepository(db *sql.DB) *ElevationRepository {
+	return &ElevationRepository{db: db}
+}
+
+func scanElevation(row rowScanner) (*models.ElevationRequest, error) {
+	e := &models.ElevationRequest{}
+	err := row.Scan(
+		&e.ID, &e.OrganizationID, &e.UserID, &e.RoleID, &e.DurationMinutes, &e.Justification, &e.CreatedAt, &e.ExpiresAt,
+		&e.DecidedBy, &e.DecisionNote, &e.ApprovedAt, &e.DeniedAt, &e.CancelledAt, &e.GrantExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	e.Status = e.StatusAt(time.Now())
+	return e, nil
+}
+
+func (r *ElevationRepository) Create(e *models.ElevationRequest) error {
+	query := `
+		INSERT INTO elevation_requests (id, organization_id, user_id, role_id, duration_minutes, justification, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.db.Exec(query, e.ID, e.OrganizationID, e.UserID, e.RoleID, e.DurationMinutes, e.Justification, e.CreatedAt, e.ExpiresAt)
+	return err
+}
+
+func (r *ElevationRepository) GetByID(id uuid.UUID) (*models.ElevationRequest, error) {
+	query := `SELECT ` + elevationColumns + ` FROM elevation_requests WHERE id = $1`
+	return scanElevation(r.db.QueryRow(query, id))
+}
+
+// HasPending reports whether the user already waits for a decision on the
+// role.
+func (r *ElevationRepository) HasPending(orgID, userID uuid.UUID, roleID int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM elevation_requests
+		WHERE organization_id = $1 AND user_id = $2 AND role_id = $3 AND ` + elevationPending + `)`
+	var exists bool
+	err := r.db.QueryRow(query, orgID, userID, roleID).Scan(&exists)
+	return exists, err
+}
+
+// ListPending returns the organization's pending requests, only userID's
+// when it is set.
+func (r *ElevationRepository) ListPending(orgID uuid.UUID, userID *uuid.UUID) ([]models.ElevationRequest, error) {
+	return r.list(elevationPending, orgID, userID)
+}
+
+// ListActive returns the approved requests whose grant has not run out yet,
+// only userID's when it is set.
+func (r *ElevationRepository) ListActive(orgID uuid.UUID, userID *uuid.UUID) ([]models.ElevationRequest, error) {
+	return r.list(`approved_at IS NOT NULL AND grant_expires_at > NOW()`, orgID, userID)
+}
+
+func (r *ElevationRepository) list(condition string, orgID uuid.UUID, userID *uuid.UUID) ([]models.ElevationRequest, error) {
+	where := " WHERE organization_id = $1 AND " + condition
+	args := []interface{}{orgID}
+
+	if userID != nil {
+		args = append(args, *userID)
+		where += fmt.Sprintf(" AND user_id = $%d", len(args))
+	}
+
+	query := `SELECT ` + elevationColumns + ` FROM elevation_requests` + where + ` ORDER BY created_at DESC`
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []models.ElevationRequest
+	for rows.Next() {
+		e, err := scanElevation(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, *e)
+	}
+	return requests, nil
+}
+
+// Approve marks a pending request approved and grants its role until
+// grantExpiresAt, in one transaction. It reports false when the request was
+// no longer pending. A grant the user already holds is only ever extended,
+// never shortened or made permanent.
+func (r *ElevationRepository) Approve(e *models.ElevationRequest, approvedBy uuid.UUID, note string, grantExpiresAt time.Time) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE elevation_requests SET approved_at = NOW(), decided_by = $1, decision_note = $2, grant_expires_at = $3
+		WHERE id = $4 AND `+elevationPending, approvedBy, note, grantExpiresAt, e.ID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO user_roles (user_id, organization_id, role_id, assigned_by, assigned_at, starts_at, expires_at)
+		VALUES ($1, $2, $3, $4, NOW(), NULL, $5)
+		ON CONFLICT (user_id, organization_id, role_id) DO UPDATE
+		SET assigned_by = EXCLUDED.assigned_by, assigned_at = EXCLUDED.assigned_at,
+			starts_at = NULL, expires_at = EXCLUDED.expires_at
+		WHERE user_roles.expires_at IS NOT NULL AND user_roles.expires_at < EXCLUDED.expires_at
+	`, e.UserID, e.OrganizationID, e.RoleID, approvedBy, grantExpiresAt)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// Deny marks a pending request denied and reports false when it was no
+// longer pending.
+func (r *ElevationRepository) Deny(id, deniedBy uuid.UUID, note string) (bool, error) {
+	query := `UPDATE elevation_requests SET denied_at = NOW(), decided_by = $1, decision_note = $2
+		WHERE id = $3 AND ` + elevationPending
+	result, err := r.db.Exec(query, deniedBy, note, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Cancel withdraws a pending request and reports false when it was no
+// longer pending.
+func (r *ElevationRepository) Cancel(id uuid.UUID) (bool, error) {
+	query := `UPDATE elevation_requests SET cancelled_at = NOW() WHERE id = $1 AND ` + elevationPending
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/auth-service/internal/models"
+)
+
+const geoColumns = `geo_country, geo_city, geo_latitude, geo_longitude, geo_asn, geo_as_org`
+
+// nullGeo scans the nullable geo_* columns shared by audit_events and
+// refresh_tokens.
+type nullGeo struct {
+	country   sql.NullString
+	city      sql.NullString
+	latitude  sql.NullFloat64
+	longitude sql.NullFloat64
+	asn       sql.NullInt64
+	asOrg     sql.NullString
+}
+
+func (g *nullGeo) dest() []interface{} {
+	return []interface{}{&g.country, &g.city, &g.latitude, &g.longitude, &g.asn, &g.asOrg}
+}
+
+func (g *nullGeo) location() *models.GeoLocation {
+	if !g.country.Valid && !g.asn.Valid {
+		return nil
+	}
+	return &models.GeoLocation{
+		Country:   g.country.String,
+		City:      g.city.String,
+		Latitude:  g.latitude.Float64,
+		Longitude: g.longitude.Float64,
+		ASN:       uint(g.asn.Int64),
+		ASOrg:     g.asOrg.String,
+	}
+}
+
+func geoValues(geo *models.GeoLocation) []interface{} {
+	if geo == nil {
+		return []interface{}{nil, nil, nil, nil, nil, nil}
+	}
+
+	values := []interface{}{nullString(geo.Country), nullString(geo.City), nil, nil, nil, nullString(geo.ASOrg)}
+	if geo.HasCoordinates() {
+		values[2], values[3] = geo.Latitude, geo.Longitude
+	}
+	if geo.ASN != 0 {
+		values[4] = int64(geo.ASN)
+	}
+	return values
+}
+
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const groupColumns = `g.id, g.organization_id, g.name, COALESCE(g.description, ''), g.created_at, g.updated_at,
+	COALESCE((SELECT array_agg(role_id ORDER BY role_id) FROM group_roles WHERE group_id = g.id), '{}'),
+	(SELECT COUNT(*) FROM group_members WHERE group_id = g.id)`
+
+type GroupRepository struct {
+	db *sql.DB
+}
+
+func NewGroupRepository(db *sql.DB) *GroupRepository {
+	return &GroupRepository{db: db}
+}
+
+func scanGroup(row rowScanner) (*models.Group, error) {
+	group := &models.Group{}
+	var roleIDs pq.Int64Array
+	err := row.Scan(&group.ID, &group.OrganizationID, &group.Name, &group.Description,
+		&group.CreatedAt, &group.UpdatedAt, &roleIDs, &group.MemberCount)
+	if err != nil {
+		return nil, err
+	}
+
+	group.RoleIDs = make([]int, len(roleIDs))
+	for i, id := range roleIDs {
+		group.RoleIDs[i] = int(id)
+	}
+	return group, nil
+}
+
+func (r *GroupRepository) Create(group *models.Group) error {
+	query := `
+		INSERT INTO groups (id, organization_id, name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(query, group.ID, group.OrganizationID, group.Name, group.Description,
+		group.CreatedAt, group.UpdatedAt)
+	return err
+}
+
+func (r *GroupRepository) GetByID(id uuid.UUID) (*models.Group, error) {
+	query := `SELECT ` + groupColumns + ` FROM groups g WHERE g.id = $1`
+	return scanGroup(r.db.QueryRow(query, id))
+}
+
+func (r *GroupRepository) GetByName(orgID uuid.UUID, name string) (*models.Group, error) {
+	query := `SELECT ` + groupColumns + ` FROM groups g WHERE g.organization_id = $1 AND g.name = $2`
+	return scanGroup(r.db.QueryRow(query, orgID, name))
+}
+
+func (r *GroupRepository) ListByOrganization(orgID uuid.UUID) ([]models.Group, error) {
+	query := `SELECT ` + groupColumns + ` FROM groups g WHERE g.organization_id = $1 ORDER BY g.name`
+	rows, err := r.db.Query(query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []models.Group
+	for rows.Next() {
+		group, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *group)
+	}
+	return groups, nil
+}
+
+func (r *GroupRepository) Update(group *models.Group) error {
+	query := `UPDATE groups SET name = $1, description = $2, updated_at = $3 WHERE id = $4`
+	_, err := r.db.Exec(query, group.Name, group.Description, group.UpdatedAt, group.ID)
+	return err
+}
+
+func (r *GroupRepository) Delete(id uuid.UUID) error {
+	_, err := r.db.Exec("DELETE FROM groups WHERE id = $1", id)
+	return err
+}
+
+func (r *GroupRepository) AssignRole(groupID uuid.UUID, roleID int, assignedBy uuid.UUID) error {
+	query := `
+		INSERT INTO group_roles (group_id, role_id, assigned_by, assigned_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (group_id, role_id) DO NOTHING
+	`
+	_, err := r.db.Exec(query, groupID, roleID, assignedBy)
+	return err
+}
+
+func (r *GroupRepository) UnassignRole(groupID uuid.UUID, roleID int) (bool, error) {
+	result, err := r.db.Exec("DELETE FROM group_roles WHERE group_id = $1 AND role_id = $2", groupID, roleID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *GroupRepository) ListMembers(groupID uuid.UUID) ([]models.GroupMember, error) {
+	query := `
+		SELECT u.id, u.email, u.display_name, gm.added_by, gm.added_at
+		FROM group_members gm
+		INNER JOIN users u ON gm.user_id = u.id
+		WHERE gm.group_id = $1
+		ORDER BY gm.added_at, u.email
+	`
+	rows, err := r.db.Query(query, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.GroupMember
+	for rows.Next() {
+		var m models.GroupMember
+		if err := rows.Scan(&m.UserID, &m.Email, &m.DisplayName, &m.AddedBy, &m.AddedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+func (r *GroupRepository) AddMember(groupID, userID, addedBy uuid.UUID) (bool, error) {
+	query := `
+		INSERT INTO group_members (group_id, user_id, added_by, added_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (group_id, user_id) DO NOTHING
+	`
+	result, err := r.db.Exec(query, groupID, userID, addedBy)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *GroupRepository) RemoveMember(groupID, userID uuid.UUID) (bool, error) {
+	result, err := r.db.Exec("DELETE FROM group_members WHERE group_id = $1 AND user_id = $2", groupID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// HasMember reports whether the user belongs to the group, which must be
+// one of the organization's groups.
+func (r *GroupRepository) HasMember(orgID, groupID, userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM group_members gm
+			INNER JOIN groups g ON gm.group_id = g.id
+			WHERE g.id = $1 AND g.organization_id = $2 AND gm.user_id = $3
+		)
+	`
+	var exists bool
+	err := r.db.QueryRow(query, groupID, orgID, userID).Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const invitationColumns = `id, organization_id, email, display_name, role_ids, token_hash, invited_by,
+	created_at, expires_at, accepted_at, revoked_at, user_id`
+
+type InvitationRepository struct {
+	db *sql.DB
+}
+
+func NewInvitationRepository(db *sql.DB) *InvitationRepository {
+	return &InvitationRepository{db: db}
+}
+
+func scanInvitation(row rowScanner) (*models.Invitation, error) {
+	inv := &models.Invitation{}
+	var displayName sql.NullString
+	var roleIDs pq.Int64Array
+	err := row.Scan(
+		&inv.ID, &inv.OrganizationID, &inv.Email, &displayName, &roleIDs, &inv.TokenHash, &inv.InvitedBy,
+		&inv.CreatedAt, &inv.ExpiresAt, &inv.AcceptedAt, &inv.RevokedAt, &inv.UserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	inv.DisplayName = displayName.String
+	inv.RoleIDs = make([]int, len(roleIDs))
+	for i, id := range roleIDs {
+		inv.RoleIDs[i] = int(id)
+	}
+	inv.Status = inv.StatusAt(time.Now())
+	return inv, nil
+}
+
+func (r *InvitationRepository) Create(inv *models.Invitation) error {
+	query := `
+		INSERT INTO user_invitations (id, organization_id, email, display_name, role_ids, token_hash, invited_by, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := r.db.Exec(query, inv.ID, inv.OrganizationID, inv.Email, inv.DisplayName, pq.Array(inv.RoleIDs),
+		inv.TokenHash, inv.InvitedBy, inv.CreatedAt, inv.ExpiresAt)
+	return err
+}
+
+func (r *InvitationRepository) GetByID(id uuid.UUID) (*models.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM user_invitations WHERE id = $1`
+	return scanInvitation(r.db.QueryRow(query, id))
+}
+
+func (r *InvitationRepository) GetByTokenHash(hash string) (*models.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM user_invitations WHERE token_hash = $1`
+	return scanInvitation(r.db.QueryRow(query, hash))
+}
+
+// GetPendingByEmailInOrganization returns the pending invitation to one
+// organization for the email, if any.
+func (r *InvitationRepository) GetPendingByEmailInOrganization(orgID uuid.UUID, email string) (*models.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM user_invitations
+		WHERE organization_id = $1 AND email = $2 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > $3
+		ORDER BY created_at DESC LIMIT 1`
+	return scanInvitation(r.db.QueryRow(query, orgID, email, time.Now()))
+}
+
+func (r *InvitationRepository) ListPending(orgID uuid.UUID) ([]models.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM user_invitations
+		WHERE organization_id = $1 AND accepted_at IS NULL AND revoked_at IS NULL
+		ORDER BY created_at DESC`
+	rows, err := r.db.Query(query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []models.Invitation
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, *inv)
+	}
+	return invitations, nil
+}
+
+func (r *InvitationRepository) UpdateToken(id uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	query := `UPDATE user_invitations SET token_hash = $1, expires_at = $2 WHERE id = $3`
+	_, err := r.db.Exec(query, tokenHash, expiresAt, id)
+	return err
+}
+
+func (r *InvitationRepository) MarkAccepted(id, userID uuid.UUID) error {
+	query := `UPDATE user_invitations SET accepted_at = $1, user_id = $2 WHERE id = $3`
+	_, err := r.db.Exec(query, time.Now(), userID, id)
+	return err
+}
+
+func (r *InvitationRepository) Revoke(id uuid.UUID) error {
+	query := `UPDATE user_invitations SET revoked_at = $1 WHERE id = $2`
+	_, err := r.db.Exec(query, time.Now(), id)
+	return err
+}
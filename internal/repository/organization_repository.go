@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+const organizationColumns = `id, slug, name, created_at`
+
+type OrganizationRepository struct {
+	db *sql.DB
+}
+
+func NewOrganizationRepository(db *sql.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+func scanOrganization(row rowScanner) (*models.Organization, error) {
+	org := &models.Organization{}
+	if err := row.Scan(&org.ID, &org.Slug, &org.Name, &org.CreatedAt); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (r *OrganizationRepository) Create(org *models.Organization) error {
+	query := `INSERT INTO organizations (id, slug, name, created_at) VALUES ($1, $2, $3, $4)`
+	_, err := r.db.Exec(query, org.ID, org.Slug, org.Name, org.CreatedAt)
+	return err
+}
+
+func (r *OrganizationRepository) GetByID(id uuid.UUID) (*models.Organization, error) {
+	query := `SELECT ` + organizationColumns + ` FROM organizations WHERE id = $1`
+	return scanOrganization(r.db.QueryRow(query, id))
+}
+
+func (r *OrganizationRepository) GetBySlug(slug string) (*models.Organization, error) {
+	query := `SELECT ` + organizationColumns + ` FROM organizations WHERE slug = $1`
+	return scanOrganization(r.db.QueryRow(query, slug))
+}
+
+// ListMemberships returns the organizations the user belongs to, oldest
+// membership first. Roles are left for the caller to fill in.
+func (r *OrganizationRepository) ListMemberships(userID uuid.UUID) ([]models.OrganizationMembership, error) {
+	query := `
+		SELECT o.id, o.slug, o.name, o.created_at, m.joined_at
+		FROM organizations o
+		INNER JOIN organization_members m ON o.id = m.organization_id
+		WHERE m.user_id = $1
+		ORDER BY m.joined_at, o.id
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []models.OrganizationMembership
+	for rows.Next() {
+		var m models.OrganizationMembership
+		org := &m.Organization
+		if err := rows.Scan(&org.ID, &org.Slug, &org.Name, &org.CreatedAt, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, m)
+	}
+	return memberships, nil
+}
+
+func (r *OrganizationRepository) IsMember(orgID, userID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM organization_members WHERE organization_id = $1 AND user_id = $2)`
+	var exists bool
+	err := r.db.QueryRow(query, orgID, userID).Scan(&exists)
+	return exists, err
+}
+
+// DefaultForUser picks the organization a login lands in when the client
+// does not ask for one: the default organization if the user belongs to it,
+// otherwise the one they joined first.
+func (r *OrganizationRepository) DefaultForUser(userID uuid.UUID) (uuid.UUID, error) {
+	query := `
+		SELECT organization_id FROM organization_members
+		WHERE user_id = $1
+		ORDER BY organization_id = $2 DESC, joined_at
+		LIMIT 1
+	`
+	var orgID uuid.UUID
+	err := r.db.QueryRow(query, userID, models.DefaultOrganizationID).Scan(&orgID)
+	return orgID, err
+}
+
+func (r *OrganizationRepository) AddMember(orgID, userID uuid.UUID) (bool, error) {
+	query := `
+		INSERT INTO organization_members (organization_id, user_id, joined_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT DO NOTHING
+	`
+	result, err := r.db.Exec(query, orgID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// RemoveMember drops the membership together with every role the user holds
+// in the organization and their membership of its groups.
+func (r *OrganizationRepository) RemoveMember(orgID, userID uuid.UUID) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM user_roles WHERE organization_id = $1 AND user_id = $2", orgID, userID); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(
+		"DELETE FROM group_members WHERE user_id = $2 AND group_id IN (SELECT id FROM groups WHERE organization_id = $1)",
+		orgID, userID,
+	); err != nil {
+		return false, err
+	}
+	result, err := tx.Exec("DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2", orgID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, tx.Commit()
+}
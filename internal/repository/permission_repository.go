@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+type PermissionRepository struct {
+	db *sql.DB
+}
+
+func NewPermissionRepository(db *sql.DB) *PermissionRepository {
+	return &PermissionRepository{db: db}
+}
+
+func (r *PermissionRepository) listPermissions(query string, args ...interface{}) ([]models.Permission, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var permissions []models.Permission
+	for rows.Next() {
+		var p models.Permission
+		if err := rows.Scan(&p.ID, &p.Name, &p.Description); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+	return permissions, nil
+}
+
+func (r *PermissionRepository) Create(permission *models.Permission) error {
+	query := `INSERT INTO permissions (name, description) VALUES ($1, $2) RETURNING id`
+	return r.db.QueryRow(query, permission.Name, permission.Description).Scan(&permission.ID)
+}
+
+func (r *PermissionRepository) GetByID(id int) (*models.Permission, error) {
+	query := `SELECT id, name, COALESCE(description, '') FROM permissions WHERE id = $1`
+	p := &models.Permission{}
+	err := r.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Description)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *PermissionRepository) GetByName(name string) (*models.Permission, error) {
+	query := `SELECT id, name, COALESCE(description, '') FROM permissions WHERE name = $1`
+	p := &models.Permission{}
+	err := r.db.QueryRow(query, name).Scan(&p.ID, &p.Name, &p.Description)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *PermissionRepository) Delete(id int) error {
+	_, err := r.db.Exec("DELETE FROM permissions WHERE id = $1", id)
+	return err
+}
+
+func (r *PermissionRepository) List() ([]models.Permission, error) {
+	return r.listPermissions(`SELECT id, name, COALESCE(description, '') FROM permissions ORDER BY name`)
+}
+
+func (r *PermissionRepository) ListByRole(roleID int) ([]models.Permission, error) {
+	query := `
+		SELECT p.id, p.name, COALESCE(p.description, '')
+		FROM permissions p
+		INNER JOIN role_permissions rp ON p.id = rp.permission_id
+		WHERE rp.role_id = $1
+		ORDER BY p.name
+	`
+	return r.listPermissions(query, roleID)
+}
+
+func (r *PermissionRepository) GrantToRole(roleID, permissionID int) error {
+	query := `
+		INSERT INTO role_permissions (role_id, permission_id) VALUES ($1, $2)
+		ON CONFLICT (role_id, permission_id) DO NOTHING
+	`
+	_, err := r.db.Exec(query, roleID, permissionID)
+	return err
+}
+
+func (r *PermissionRepository) RevokeFromRole(roleID, permissionID int) error {
+	_, err := r.db.Exec("DELETE FROM role_permissions WHERE role_id = $1 AND permission_id = $2", roleID, permissionID)
+	return err
+}
+
+// GetUserPermissions returns the names of all permissions granted to any of
+// the user's roles in the organization, including inherited ones.
+func (r *PermissionRepository) GetUserPermissions(userID, orgID uuid.UUID) ([]string, error) {
+	query := effectiveRolesCTE + `
+		SELECT DISTINCT p.name
+		FROM permissions p
+		INNER JOIN role_permissions rp ON p.id = rp.permission_id
+		INNER JOIN effective_roles er ON rp.role_id = er.role_id
+		ORDER BY p.name
+	`
+	rows, err := r.db.Query(query, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+const relationTupleColumns = `namespace, object_id, relation, subject_namespace, subject_id, subject_relation, created_by, created_at`
+
+type RelationRepository struct {
+	db *sql.DB
+}
+
+func NewRelationRepository(db *sql.DB) *RelationRepository {
+	return &RelationRepository{db: db}
+}
+
+func scanRelationTuple(row rowScanner) (*models.RelationTuple, error) {
+	t := &models.RelationTuple{}
+	err := row.Scan(&t.Namespace, &t.ObjectID, &t.Relation,
+		&t.Subject.Namespace, &t.Subject.ID, &t.Subject.Relation, &t.CreatedBy, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *RelationRepository) List(orgID uuid.UUID, filter models.RelationTupleFilter) ([]models.RelationTuple, error) {
+	where := " WHERE organization_id = $1"
+	args := []interface{}{orgID}
+
+	if filter.Namespace != "" {
+		args = append(args, filter.Namespace)
+		where += fmt.Sprintf(" AND namespace = $%d", len(args))
+	}
+	if filter.ObjectID != "" {
+		args = append(args, filter.ObjectID)
+		where += fmt.Sprintf(" AND object_id = $%d", len(args))
+	}
+	if filter.Relation != "" {
+		args = append(args, filter.Relation)
+		where += fmt.Sprintf(" AND relation = $%d", len(args))
+	}
+
+	query := `SELECT ` + relationTupleColumns + ` FROM relation_tuples` + where +
+		` ORDER BY namespace, object_id, relation, subject_namespace, subject_id, subject_relation LIMIT 1000`
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tuples []models.RelationTuple
+	for rows.Next() {
+		t, err := scanRelationTuple(rows)
+		if err != nil {
+			return nil, err
+		}
+		tuples = append(tuples, *t)
+	}
+	return tuples, nil
+}
+
+// ListSubjects returns the subjects stored directly for one relation of an
+// object.
+func (r *RelationRepository) ListSubjects(orgID uuid.UUID, namespace, objectID, relation string) ([]models.RelationSubject, error) {
+	query := `
+		SELECT subject_namespace, subject_id, subject_relation
+		FROM relation_tuples
+		WHERE organization_id = $1 AND namespace = $2 AND object_id = $3 AND relation = $4
+		ORDER BY subject_namespace, subject_id, subject_relation
+	`
+	rows, err := r.db.Query(query, orgID, namespace, objectID, relation)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subjects []models.RelationSubject
+	for rows.Next() {
+		var s models.RelationSubject
+		if err := rows.Scan(&s.Namespace, &s.ID, &s.Relation); err != nil {
+			return nil, err
+		}
+		subjects = append(subjects, s)
+	}
+	return subjects, nil
+}
+
+// ListObjectIDs returns every object of the namespace that appears in at
+// least one tuple.
+func (r *RelationRepository) ListObjectIDs(orgID uuid.UUID, namespace string) ([]string, error) {
+	query := `SELECT DISTINCT object_id FROM relation_tuples WHERE organization_id = $1 AND namespace = $2 ORDER BY object_id`
+	rows, err := r.db.Query(query, orgID, namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Apply writes and deletes tuples in one transaction and reports how many
+// rows actually changed; existing writes and missing deletes are ignored.
+func (r *RelationRepository) Apply(orgID uuid.UUID, writes, deletes []models.RelationTuple, createdBy uuid.UUID) (int, int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	written := 0
+	for _, t := range writes {
+		result, err := tx.Exec(`
+			INSERT INTO relation_tuples (organization_id, namespace, object_id, relation,
+				subject_namespace, subject_id, subject_relation, created_by, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+			ON CONFLICT DO NOTHING
+		`, orgID, t.Namespace, t.ObjectID, t.Relation, t.Subject.Namespace, t.Subject.ID, t.Subject.Relation, createdBy)
+		if err != nil {
+			return 0, 0, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, 0, err
+		}
+		written += int(affected)
+	}
+
+	deleted := 0
+	for _, t := range deletes {
+		result, err := tx.Exec(`
+			DELETE FROM relation_tuples
+			WHERE organization_id = $1 AND namespace = $2 AND object_id = $3 AND relation = $4
+				AND subject_namespace = $5 AND subject_id = $6 AND subject_relation = $7
+		`, orgID, t.Namespace, t.ObjectID, t.Relation, t.Subject.Namespace, t.Subject.ID, t.Subject.Relation)
+		if err != nil {
+			return 0, 0, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, 0, err
+		}
+		deleted += int(affected)
+	}
+
+	return written, deleted, tx.Commit()
+}
@@ -2,11 +2,40 @@ package repository
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/auth-service/internal/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const roleColumns = `id, name, description, max_sessions, is_system`
+
+// effectiveRolesCTE expands the roles granted to user $1 in organization $2,
+// directly or through the groups they belong to, with every role they
+// inherit, transitively. Direct grants outside their validity window are
+// skipped. UNION drops duplicates, which also ends the
+// recursion should a cycle ever make it into role_inheritance.
+const effectiveRolesCTE = `
+	WITH RECURSIVE granted_roles(role_id) AS (
+		SELECT role_id FROM user_roles
+		WHERE user_id = $1 AND organization_id = $2
+			AND (starts_at IS NULL OR starts_at <= NOW())
+			AND (expires_at IS NULL OR expires_at > NOW())
+		UNION
+		SELECT gr.role_id FROM group_roles gr
+		INNER JOIN group_members gm ON gr.group_id = gm.group_id
+		INNER JOIN groups g ON gr.group_id = g.id
+		WHERE gm.user_id = $1 AND g.organization_id = $2
+	),
+	effective_roles(role_id) AS (
+		SELECT role_id FROM granted_roles
+		UNION
+		SELECT ri.inherits_role_id FROM role_inheritance ri
+		INNER JOIN effective_roles er ON ri.role_id = er.role_id
+	)
+`
+
 type RoleRepository struct {
 	db *sql.DB
 }
@@ -15,34 +44,51 @@ func NewRoleRepository(db *sql.DB) *RoleRepository {
 	return &RoleRepository{db: db}
 }
 
-func (r *RoleRepository) Create(role *models.Role) error {
-	query := `INSERT INTO roles (name, description) VALUES ($1, $2) RETURNING id`
-	return r.db.QueryRow(query, role.Name, role.Description).Scan(&role.ID)
-}
-
-func (r *RoleRepository) GetByID(id int) (*models.Role, error) {
-	query := `SELECT id, name, description FROM roles WHERE id = $1`
+func scanRole(row rowScanner) (*models.Role, error) {
 	role := &models.Role{}
-	err := r.db.QueryRow(query, id).Scan(&role.ID, &role.Name, &role.Description)
+	err := row.Scan(&role.ID, &role.Name, &role.Description, &role.MaxSessions, &role.IsSystem)
 	if err != nil {
 		return nil, err
 	}
 	return role, nil
 }
 
-func (r *RoleRepository) GetByName(name string) (*models.Role, error) {
-	query := `SELECT id, name, description FROM roles WHERE name = $1`
-	role := &models.Role{}
-	err := r.db.QueryRow(query, name).Scan(&role.ID, &role.Name, &role.Description)
+func (r *RoleRepository) listRoles(query string, args ...interface{}) ([]models.Role, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
-	return role, nil
+	defer rows.Close()
+
+	var roles []models.Role
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, *role)
+	}
+	return roles, nil
+}
+
+func (r *RoleRepository) Create(role *models.Role) error {
+	query := `INSERT INTO roles (name, description, max_sessions) VALUES ($1, $2, $3) RETURNING id`
+	return r.db.QueryRow(query, role.Name, role.Description, role.MaxSessions).Scan(&role.ID)
+}
+
+func (r *RoleRepository) GetByID(id int) (*models.Role, error) {
+	query := `SELECT ` + roleColumns + ` FROM roles WHERE id = $1`
+	return scanRole(r.db.QueryRow(query, id))
+}
+
+func (r *RoleRepository) GetByName(name string) (*models.Role, error) {
+	query := `SELECT ` + roleColumns + ` FROM roles WHERE name = $1`
+	return scanRole(r.db.QueryRow(query, name))
 }
 
 func (r *RoleRepository) Update(role *models.Role) error {
-	query := `UPDATE roles SET name = $1, description = $2 WHERE id = $3`
-	_, err := r.db.Exec(query, role.Name, role.Description, role.ID)
+	query := `UPDATE roles SET name = $1, description = $2, max_sessions = $3 WHERE id = $4`
+	_, err := r.db.Exec(query, role.Name, role.Description, role.MaxSessions, role.ID)
 	return err
 }
 
@@ -52,73 +98,305 @@ func (r *RoleRepository) Delete(id int) error {
 }
 
 func (r *RoleRepository) List() ([]models.Role, error) {
-	query := `SELECT id, name, description FROM roles ORDER BY id`
-	rows, err := r.db.Query(query)
+	return r.listRoles(`SELECT ` + roleColumns + ` FROM roles ORDER BY id`)
+}
+
+// AssignRoleToUser grants a role in an organization. A nil startsAt or
+// expiresAt leaves that end of the window open; re-assigning an existing
+// grant replaces its window.
+func (r *RoleRepository) AssignRoleToUser(userID, orgID uuid.UUID, roleID int, assignedBy uuid.UUID, startsAt, expiresAt *time.Time) error {
+	query := `
+		INSERT INTO user_roles (user_id, organization_id, role_id, assigned_by, assigned_at, starts_at, expires_at)
+		VALUES ($1, $2, $3, $4, NOW(), $5, $6)
+		ON CONFLICT (user_id, organization_id, role_id) DO UPDATE
+		SET assigned_by = EXCLUDED.assigned_by, assigned_at = EXCLUDED.assigned_at,
+			starts_at = EXCLUDED.starts_at, expires_at = EXCLUDED.expires_at
+	`
+	_, err := r.db.Exec(query, userID, orgID, roleID, assignedBy, startsAt, expiresAt)
+	return err
+}
+
+// ListUserGrants returns the roles assigned directly to the user in the
+// organization, including grants that have not started or have lapsed but
+// not yet been swept.
+func (r *RoleRepository) ListUserGrants(userID, orgID uuid.UUID) ([]models.UserRole, error) {
+	query := `
+		SELECT user_id, organization_id, role_id, assigned_by, assigned_at, starts_at, expires_at
+		FROM user_roles
+		WHERE user_id = $1 AND organization_id = $2
+		ORDER BY role_id
+	`
+	return r.listGrants(query, userID, orgID)
+}
+
+// ListExpiredGrants returns every grant that lapsed at or before now.
+func (r *RoleRepository) ListExpiredGrants(now time.Time) ([]models.UserRole, error) {
+	query := `
+		SELECT user_id, organization_id, role_id, assigned_by, assigned_at, starts_at, expires_at
+		FROM user_roles
+		WHERE expires_at <= $1
+		ORDER BY organization_id, user_id, role_id
+	`
+	return r.listGrants(query, now)
+}
+
+// DeleteExpiredGrant removes the grant if it is still lapsed at now, so a
+// grant renewed since it was listed is left alone.
+func (r *RoleRepository) DeleteExpiredGrant(userID, orgID uuid.UUID, roleID int, now time.Time) (bool, error) {
+	query := `
+		DELETE FROM user_roles
+		WHERE user_id = $1 AND organization_id = $2 AND role_id = $3 AND expires_at <= $4
+	`
+	result, err := r.db.Exec(query, userID, orgID, roleID, now)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *RoleRepository) listGrants(query string, args ...interface{}) ([]models.UserRole, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var roles []models.Role
+	var grants []models.UserRole
 	for rows.Next() {
-		var role models.Role
-		if err := rows.Scan(&role.ID, &role.Name, &role.Description); err != nil {
+		var g models.UserRole
+		if err := rows.Scan(&g.UserID, &g.OrganizationID, &g.RoleID, &g.AssignedBy,
+			&g.AssignedAt, &g.StartsAt, &g.ExpiresAt); err != nil {
 			return nil, err
 		}
-		roles = append(roles, role)
+		grants = append(grants, g)
 	}
-	return roles, nil
+	return grants, nil
 }
 
-func (r *RoleRepository) AssignRoleToUser(userID uuid.UUID, roleID int, assignedBy uuid.UUID) error {
-	query := `
-		INSERT INTO user_roles (user_id, role_id, assigned_by, assigned_at)
-		VALUES ($1, $2, $3, NOW())
-		ON CONFLICT (user_id, role_id) DO NOTHING
-	`
-	_, err := r.db.Exec(query, userID, roleID, assignedBy)
+func (r *RoleRepository) UnassignRoleFromUser(userID, orgID uuid.UUID, roleID int) error {
+	query := `DELETE FROM user_roles WHERE user_id = $1 AND organization_id = $2 AND role_id = $3`
+	_, err := r.db.Exec(query, userID, orgID, roleID)
 	return err
 }
 
-func (r *RoleRepository) UnassignRoleFromUser(userID uuid.UUID, roleID int) error {
-	query := `DELETE FROM user_roles WHERE user_id = $1 AND role_id = $2`
-	_, err := r.db.Exec(query, userID, roleID)
+// RemoveAllUserRoles drops every direct grant and group membership, so the
+// user is left with no roles in any organization.
+func (r *RoleRepository) RemoveAllUserRoles(userID uuid.UUID) error {
+	if _, err := r.db.Exec("DELETE FROM user_roles WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	_, err := r.db.Exec("DELETE FROM group_members WHERE user_id = $1", userID)
 	return err
 }
 
-func (r *RoleRepository) GetUserRoles(userID uuid.UUID) ([]models.Role, error) {
-	query := `
-		SELECT r.id, r.name, r.description
+func (r *RoleRepository) GetUserRoles(userID, orgID uuid.UUID) ([]models.Role, error) {
+	query := effectiveRolesCTE + `
+		SELECT r.id, r.name, r.description, r.max_sessions, r.is_system
 		FROM roles r
-		INNER JOIN user_roles ur ON r.id = ur.role_id
-		WHERE ur.user_id = $1
+		INNER JOIN effective_roles er ON r.id = er.role_id
+		ORDER BY r.id
 	`
-	rows, err := r.db.Query(query, userID)
+	return r.listRoles(query, userID, orgID)
+}
+
+// CountActiveHolders counts the active, unsuspended users outside
+// excludeUserIDs that hold the role in the organization, whether directly,
+// through a group or by inheritance.
+func (r *RoleRepository) CountActiveHolders(orgID uuid.UUID, roleName string, excludeUserIDs []uuid.UUID) (int, error) {
+	query := `
+		WITH RECURSIVE granted_roles(user_id, role_id) AS (
+			SELECT user_id, role_id FROM user_roles
+			WHERE organization_id = $1
+				AND (starts_at IS NULL OR starts_at <= NOW())
+				AND (expires_at IS NULL OR expires_at > NOW())
+			UNION
+			SELECT gm.user_id, gr.role_id FROM group_roles gr
+			INNER JOIN group_members gm ON gr.group_id = gm.group_id
+			INNER JOIN groups g ON gr.group_id = g.id
+			WHERE g.organization_id = $1
+		),
+		effective_roles(user_id, role_id) AS (
+			SELECT user_id, role_id FROM granted_roles
+			UNION
+			SELECT er.user_id, ri.inherits_role_id FROM role_inheritance ri
+			INNER JOIN effective_roles er ON ri.role_id = er.role_id
+		)
+		SELECT COUNT(DISTINCT u.id)
+		FROM effective_roles er
+		INNER JOIN roles r ON er.role_id = r.id
+		INNER JOIN users u ON er.user_id = u.id
+		WHERE r.name = $2 AND u.id <> ALL($3::uuid[])
+			AND u.is_active AND u.erased_at IS NULL AND u.deletion_scheduled_for IS NULL
+			AND (u.suspended_at IS NULL OR u.suspended_until <= NOW())
+	`
+	excluded := make(pq.StringArray, len(excludeUserIDs))
+	for i, id := range excludeUserIDs {
+		excluded[i] = id.String()
+	}
+
+	var count int
+	err := r.db.QueryRow(query, orgID, roleName, excluded).Scan(&count)
+	return count, err
+}
+
+// ListRoleHolders returns, per organization, the users that currently hold
+// the role there, whether directly, through a group or by inheriting it from
+// another role they hold.
+func (r *RoleRepository) ListRoleHolders(roleID int) (map[uuid.UUID][]uuid.UUID, error) {
+	query := `
+		WITH RECURSIVE granted_roles(user_id, organization_id, role_id) AS (
+			SELECT user_id, organization_id, role_id FROM user_roles
+			WHERE (starts_at IS NULL OR starts_at <= NOW())
+				AND (expires_at IS NULL OR expires_at > NOW())
+			UNION
+			SELECT gm.user_id, g.organization_id, gr.role_id FROM group_roles gr
+			INNER JOIN group_members gm ON gr.group_id = gm.group_id
+			INNER JOIN groups g ON gr.group_id = g.id
+		),
+		effective_roles(user_id, organization_id, role_id) AS (
+			SELECT user_id, organization_id, role_id FROM granted_roles
+			UNION
+			SELECT er.user_id, er.organization_id, ri.inherits_role_id FROM role_inheritance ri
+			INNER JOIN effective_roles er ON ri.role_id = er.role_id
+		)
+		SELECT DISTINCT organization_id, user_id
+		FROM effective_roles
+		WHERE role_id = $1
+		ORDER BY organization_id, user_id
+	`
+	rows, err := r.db.Query(query, roleID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var roles []models.Role
+	holders := make(map[uuid.UUID][]uuid.UUID)
 	for rows.Next() {
-		var role models.Role
-		if err := rows.Scan(&role.ID, &role.Name, &role.Description); err != nil {
+		var orgID, userID uuid.UUID
+		if err := rows.Scan(&orgID, &userID); err != nil {
 			return nil, err
 		}
-		roles = append(roles, role)
+		holders[orgID] = append(holders[orgID], userID)
 	}
-	return roles, nil
+	return holders, nil
 }
 
-func (r *RoleRepository) UserHasRole(userID uuid.UUID, roleName string) (bool, error) {
-	query := `
+func (r *RoleRepository) UserHasRole(userID, orgID uuid.UUID, roleName string) (bool, error) {
+	query := effectiveRolesCTE + `
 		SELECT EXISTS(
-			SELECT 1 FROM user_roles ur
-			INNER JOIN roles r ON ur.role_id = r.id
-			WHERE ur.user_id = $1 AND r.name = $2
+			SELECT 1 FROM effective_roles er
+			INNER JOIN roles r ON er.role_id = r.id
+			WHERE r.name = $3
 		)
 	`
 	var exists bool
-	err := r.db.QueryRow(query, userID, roleName).Scan(&exists)
+	err := r.db.QueryRow(query, userID, orgID, roleName).Scan(&exists)
 	return exists, err
 }
+
+// InheritanceGraph returns, for every role that inherits others, the IDs of
+// the roles it inherits directly.
+func (r *RoleRepository) InheritanceGraph() (map[int][]int, error) {
+	rows, err := r.db.Query(`SELECT role_id, inherits_role_id FROM role_inheritance ORDER BY role_id, inherits_role_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	graph := make(map[int][]int)
+	for rows.Next() {
+		var roleID, inheritsID int
+		if err := rows.Scan(&roleID, &inheritsID); err != nil {
+			return nil, err
+		}
+		graph[roleID] = append(graph[roleID], inheritsID)
+	}
+	return graph, nil
+}
+
+func (r *RoleRepository) SetInheritedRoles(roleID int, inherits []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM role_inheritance WHERE role_id = $1", roleID); err != nil {
+		return err
+	}
+	for _, inheritsID := range inherits {
+		if _, err := tx.Exec(
+			"INSERT INTO role_inheritance (role_id, inherits_role_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			roleID, inheritsID,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ManagementScopes returns, for every role with a role-management scope, the
+// IDs of the roles its holders may assign and unassign.
+func (r *RoleRepository) ManagementScopes() (map[int][]int, error) {
+	rows, err := r.db.Query(`SELECT manager_role_id, managed_role_id FROM role_management_scopes ORDER BY manager_role_id, managed_role_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scopes := make(map[int][]int)
+	for rows.Next() {
+		var managerID, managedID int
+		if err := rows.Scan(&managerID, &managedID); err != nil {
+			return nil, err
+		}
+		scopes[managerID] = append(scopes[managerID], managedID)
+	}
+	return scopes, nil
+}
+
+func (r *RoleRepository) SetManagedRoles(roleID int, manages []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM role_management_scopes WHERE manager_role_id = $1", roleID); err != nil {
+		return err
+	}
+	for _, managedID := range manages {
+		if _, err := tx.Exec(
+			"INSERT INTO role_management_scopes (manager_role_id, managed_role_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			roleID, managedID,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CanManageRole reports whether the user may assign or unassign the role in
+// the organization: either one of their roles grants anyRolePermission, or
+// one of their roles has the role in its management scope.
+func (r *RoleRepository) CanManageRole(userID, orgID uuid.UUID, roleID int, anyRolePermission string) (bool, error) {
+	query := effectiveRolesCTE + `
+		SELECT EXISTS(
+			SELECT 1 FROM effective_roles er
+			INNER JOIN role_permissions rp ON er.role_id = rp.role_id
+			INNER JOIN permissions p ON rp.permission_id = p.id
+			WHERE p.name = $4
+		) OR EXISTS(
+			SELECT 1 FROM effective_roles er
+			INNER JOIN role_management_scopes rms ON er.role_id = rms.manager_role_id
+			WHERE rms.managed_role_id = $3
+		)
+	`
+	var allowed bool
+	err := r.db.QueryRow(query, userID, orgID, roleID, anyRolePermission).Scan(&allowed)
+	return allowed, err
+}
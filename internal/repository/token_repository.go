@@ -18,30 +18,34 @@ func NewTokenRepository(db *sql.DB) *TokenRepository {
 
 func (r *TokenRepository) CreateRefreshToken(token *models.RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (id, user_id, token_hash, issued_at, expires_at, revoked, user_agent, ip_address)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO refresh_tokens (id, user_id, session_id, organization_id, token_hash, issued_at, session_started_at, expires_at, revoked, user_agent, ip_address, ` + geoColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 	`
-	_, err := r.db.Exec(query, token.ID, token.UserID, token.TokenHash, token.IssuedAt,
-		token.ExpiresAt, token.Revoked, token.UserAgent, token.IPAddress)
+	args := []interface{}{token.ID, token.UserID, token.SessionID, token.OrganizationID, token.TokenHash, token.IssuedAt,
+		token.SessionStartedAt, token.ExpiresAt, token.Revoked, token.UserAgent, token.IPAddress}
+	_, err := r.db.Exec(query, append(args, geoValues(token.Geo)...)...)
 	return err
 }
 
-func (r *TokenRepository) GetRefreshTokenByHash(hash string) (*models.RefreshToken, error) {
-	query := `
-		SELECT id, user_id, token_hash, issued_at, expires_at, revoked, user_agent, ip_address
-		FROM refresh_tokens WHERE token_hash = $1
-	`
+const refreshTokenColumns = `id, user_id, session_id, organization_id, token_hash, issued_at, session_started_at, expires_at, revoked, user_agent, ip_address, ` + geoColumns
+
+func scanRefreshToken(row rowScanner) (*models.RefreshToken, error) {
 	token := &models.RefreshToken{}
-	err := r.db.QueryRow(query, hash).Scan(
-		&token.ID, &token.UserID, &token.TokenHash, &token.IssuedAt,
-		&token.ExpiresAt, &token.Revoked, &token.UserAgent, &token.IPAddress,
-	)
-	if err != nil {
+	var geo nullGeo
+	dest := append([]interface{}{&token.ID, &token.UserID, &token.SessionID, &token.OrganizationID, &token.TokenHash, &token.IssuedAt,
+		&token.SessionStartedAt, &token.ExpiresAt, &token.Revoked, &token.UserAgent, &token.IPAddress}, geo.dest()...)
+	if err := row.Scan(dest...); err != nil {
 		return nil, err
 	}
+	token.Geo = geo.location()
 	return token, nil
 }
 
+func (r *TokenRepository) GetRefreshTokenByHash(hash string) (*models.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token_hash = $1`
+	return scanRefreshToken(r.db.QueryRow(query, hash))
+}
+
 func (r *TokenRepository) RevokeRefreshToken(id uuid.UUID) error {
 	query := `UPDATE refresh_tokens SET revoked = true WHERE id = $1`
 	_, err := r.db.Exec(query, id)
@@ -54,25 +58,80 @@ func (r *TokenRepository) RevokeAllUserTokens(userID uuid.UUID) error {
 	return err
 }
 
+func (r *TokenRepository) RevokeSession(userID, sessionID uuid.UUID) (bool, error) {
+	query := `UPDATE refresh_tokens SET revoked = true WHERE user_id = $1 AND session_id = $2 AND revoked = false`
+	result, err := r.db.Exec(query, userID, sessionID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// SetSessionOrganization moves a live session to another organization; the
+// next refresh issues tokens for that organization.
+func (r *TokenRepository) SetSessionOrganization(userID, sessionID, orgID uuid.UUID) (bool, error) {
+	query := `UPDATE refresh_tokens SET organization_id = $3 WHERE user_id = $1 AND session_id = $2 AND revoked = false`
+	result, err := r.db.Exec(query, userID, sessionID, orgID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *TokenRepository) ListActiveRefreshTokens(userID uuid.UUID) ([]models.RefreshToken, error) {
+	query := `
+		SELECT ` + refreshTokenColumns + `
+		FROM refresh_tokens
+		WHERE user_id = $1 AND revoked = false AND expires_at > $2
+		ORDER BY issued_at DESC
+	`
+	rows, err := r.db.Query(query, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []models.RefreshToken
+	for rows.Next() {
+		token, err := scanRefreshToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, nil
+}
+
+func (r *TokenRepository) DeleteAllUserTokens(userID uuid.UUID) error {
+	_, err := r.db.Exec("DELETE FROM refresh_tokens WHERE user_id = $1", userID)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec("DELETE FROM email_tokens WHERE user_id = $1", userID)
+	return err
+}
+
 func (r *TokenRepository) CreateEmailToken(token *models.EmailToken) error {
 	query := `
-		INSERT INTO email_tokens (id, user_id, token_hash, type, expires_at, used)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO email_tokens (id, user_id, token_hash, type, expires_at, used, session_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 	_, err := r.db.Exec(query, token.ID, token.UserID, token.TokenHash,
-		token.Type, token.ExpiresAt, token.Used)
+		token.Type, token.ExpiresAt, token.Used, token.SessionID)
 	return err
 }
 
 func (r *TokenRepository) GetEmailTokenByHash(hash string, tokenType models.EmailTokenType) (*models.EmailToken, error) {
 	query := `
-		SELECT id, user_id, token_hash, type, expires_at, used
+		SELECT id, user_id, token_hash, type, expires_at, used, session_id
 		FROM email_tokens WHERE token_hash = $1 AND type = $2
 	`
 	token := &models.EmailToken{}
 	err := r.db.QueryRow(query, hash, tokenType).Scan(
 		&token.ID, &token.UserID, &token.TokenHash, &token.Type,
-		&token.ExpiresAt, &token.Used,
+		&token.ExpiresAt, &token.Used, &token.SessionID,
 	)
 	if err != nil {
 		return nil, err
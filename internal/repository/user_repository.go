@@ -2,12 +2,54 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/auth-service/internal/models"
 	"github.com/google/uuid"
 )
 
+const userColumns = `id, email, username, phone, password_hash, display_name, is_active, is_verified,
+	created_at, updated_at, last_login_at, failed_login_count, locked_until,
+	deletion_requested_at, deletion_scheduled_for, erased_at,
+	suspended_at, suspended_until, suspension_reason, suspended_by,
+	dormancy_warned_at, metadata, security_stamp`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
+	user := &models.User{}
+	var metadata []byte
+	err := row.Scan(
+		&user.ID, &user.Email, &user.Username, &user.Phone, &user.PasswordHash, &user.DisplayName,
+		&user.IsActive, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
+		&user.LastLoginAt, &user.FailedLoginCount, &user.LockedUntil,
+		&user.DeletionRequestedAt, &user.DeletionScheduledFor, &user.ErasedAt,
+		&user.SuspendedAt, &user.SuspendedUntil, &user.SuspensionReason, &user.SuspendedBy,
+		&user.DormancyWarnedAt, &metadata, &user.SecurityStamp,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &user.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	if user.Metadata.User == nil {
+		user.Metadata.User = map[string]interface{}{}
+	}
+	if user.Metadata.Admin == nil {
+		user.Metadata.Admin = map[string]interface{}{}
+	}
+	return user, nil
+}
+
 type UserRepository struct {
 	db *sql.DB
 }
@@ -17,59 +59,57 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 }
 
 func (r *UserRepository) Create(user *models.User) error {
+	if user.Metadata.User == nil {
+		user.Metadata.User = map[string]interface{}{}
+	}
+	if user.Metadata.Admin == nil {
+		user.Metadata.Admin = map[string]interface{}{}
+	}
+	metadata, err := json.Marshal(user.Metadata)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO users (id, email, password_hash, display_name, is_active, is_verified, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO users (id, email, username, phone, password_hash, display_name, is_active, is_verified,
+			created_at, updated_at, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
-	_, err := r.db.Exec(query, user.ID, user.Email, user.PasswordHash, user.DisplayName,
-		user.IsActive, user.IsVerified, user.CreatedAt, user.UpdatedAt)
+	_, err = r.db.Exec(query, user.ID, user.Email, user.Username, user.Phone, user.PasswordHash, user.DisplayName,
+		user.IsActive, user.IsVerified, user.CreatedAt, user.UpdatedAt, metadata)
 	return err
 }
 
 func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
-	query := `
-		SELECT id, email, password_hash, display_name, is_active, is_verified, 
-			   created_at, updated_at, last_login_at, failed_login_count, locked_until
-		FROM users WHERE id = $1
-	`
-	user := &models.User{}
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.DisplayName,
-		&user.IsActive, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
-		&user.LastLoginAt, &user.FailedLoginCount, &user.LockedUntil,
-	)
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	return scanUser(r.db.QueryRow(query, id))
 }
 
 func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
-	query := `
-		SELECT id, email, password_hash, display_name, is_active, is_verified, 
-			   created_at, updated_at, last_login_at, failed_login_count, locked_until
-		FROM users WHERE email = $1
-	`
-	user := &models.User{}
-	err := r.db.QueryRow(query, email).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.DisplayName,
-		&user.IsActive, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
-		&user.LastLoginAt, &user.FailedLoginCount, &user.LockedUntil,
-	)
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
+	return scanUser(r.db.QueryRow(query, email))
+}
+
+func (r *UserRepository) GetByUsername(username string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE username = $1`
+	return scanUser(r.db.QueryRow(query, username))
+}
+
+func (r *UserRepository) GetByPhone(phone string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE phone = $1`
+	return scanUser(r.db.QueryRow(query, phone))
 }
 
 func (r *UserRepository) Update(user *models.User) error {
 	query := `
 		UPDATE users SET email = $1, display_name = $2, is_active = $3, is_verified = $4,
-			   updated_at = $5, last_login_at = $6, failed_login_count = $7, locked_until = $8
-		WHERE id = $9
+			   updated_at = $5, last_login_at = $6, failed_login_count = $7, locked_until = $8,
+			   username = $9, phone = $10
+		WHERE id = $11
 	`
 	_, err := r.db.Exec(query, user.Email, user.DisplayName, user.IsActive, user.IsVerified,
-		time.Now(), user.LastLoginAt, user.FailedLoginCount, user.LockedUntil, user.ID)
+		time.Now(), user.LastLoginAt, user.FailedLoginCount, user.LockedUntil,
+		user.Username, user.Phone, user.ID)
 	return err
 }
 
@@ -79,60 +119,79 @@ func (r *UserRepository) UpdatePassword(userID uuid.UUID, passwordHash string) e
 	return err
 }
 
+func (r *UserRepository) GetSecurityStamp(userID uuid.UUID) (string, error) {
+	var stamp string
+	err := r.db.QueryRow(`SELECT security_stamp FROM users WHERE id = $1`, userID).Scan(&stamp)
+	return stamp, err
+}
+
+func (r *UserRepository) SetSecurityStamp(userID uuid.UUID, stamp string) error {
+	_, err := r.db.Exec(`UPDATE users SET security_stamp = $1 WHERE id = $2`, stamp, userID)
+	return err
+}
+
 func (r *UserRepository) Delete(id uuid.UUID) error {
 	_, err := r.db.Exec("DELETE FROM users WHERE id = $1", id)
 	return err
 }
 
-func (r *UserRepository) List(page, perPage int, search string) ([]models.User, int64, error) {
+func (r *UserRepository) List(page, perPage int, filter models.UserFilter) ([]models.User, int64, error) {
 	offset := (page - 1) * perPage
-	var total int64
 
-	countQuery := "SELECT COUNT(*) FROM users"
-	if search != "" {
-		countQuery += " WHERE email ILIKE $1 OR display_name ILIKE $1"
-		r.db.QueryRow(countQuery, "%"+search+"%").Scan(&total)
-	} else {
-		r.db.QueryRow(countQuery).Scan(&total)
+	where := " WHERE 1=1"
+	args := []interface{}{}
+
+	if filter.OrganizationID != uuid.Nil {
+		args = append(args, filter.OrganizationID)
+		where += fmt.Sprintf(" AND id IN (SELECT user_id FROM organization_members WHERE organization_id = $%d)", len(args))
 	}
 
-	query := `
-		SELECT id, email, password_hash, display_name, is_active, is_verified, 
-			   created_at, updated_at, last_login_at, failed_login_count, locked_until
-		FROM users
-	`
-	var rows *sql.Rows
-	var err error
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		n := len(args)
+		where += fmt.Sprintf(" AND (email ILIKE $%d OR display_name ILIKE $%d OR username ILIKE $%d OR phone ILIKE $%d)", n, n, n, n)
+	}
 
-	if search != "" {
-		query += " WHERE email ILIKE $1 OR display_name ILIKE $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3"
-		rows, err = r.db.Query(query, "%"+search+"%", perPage, offset)
-	} else {
-		query += " ORDER BY created_at DESC LIMIT $1 OFFSET $2"
-		rows, err = r.db.Query(query, perPage, offset)
+	keys := make([]string, 0, len(filter.Attributes))
+	for key := range filter.Attributes {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
 
-	if err != nil {
+	for _, key := range keys {
+		args = append(args, key, filter.Attributes[key])
+		k, v := len(args)-1, len(args)
+		where += fmt.Sprintf(" AND (metadata->'user'->>$%d = $%d OR metadata->'admin'->>$%d = $%d)", k, v, k, v)
+	}
+
+	var total int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM users"+where, args...).Scan(&total); err != nil {
 		return nil, 0, err
 	}
-	defer rows.Close()
 
-	var users []models.User
-	for rows.Next() {
-		var user models.User
-		if err := rows.Scan(
-			&user.ID, &user.Email, &user.PasswordHash, &user.DisplayName,
-			&user.IsActive, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
-			&user.LastLoginAt, &user.FailedLoginCount, &user.LockedUntil,
-		); err != nil {
-			return nil, 0, err
-		}
-		users = append(users, user)
+	query := `SELECT ` + userColumns + ` FROM users` + where +
+		fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, perPage, offset)
+
+	users, err := r.listUsers(query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	return users, total, nil
 }
 
+func (r *UserRepository) UpdateMetadata(userID uuid.UUID, metadata models.UserMetadata) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE users SET metadata = $1, updated_at = $2 WHERE id = $3`
+	_, err = r.db.Exec(query, data, time.Now(), userID)
+	return err
+}
+
 func (r *UserRepository) IncrementFailedLogin(userID uuid.UUID) error {
 	query := `UPDATE users SET failed_login_count = failed_login_count + 1 WHERE id = $1`
 	_, err := r.db.Exec(query, userID)
@@ -146,7 +205,36 @@ func (r *UserRepository) LockAccount(userID uuid.UUID, until time.Time) error {
 }
 
 func (r *UserRepository) ResetFailedLogin(userID uuid.UUID) error {
-	query := `UPDATE users SET failed_login_count = 0, locked_until = NULL, last_login_at = $1 WHERE id = $2`
+	query := `
+		UPDATE users SET failed_login_count = 0, locked_until = NULL, last_login_at = $1, dormancy_warned_at = NULL
+		WHERE id = $2
+	`
+	_, err := r.db.Exec(query, time.Now(), userID)
+	return err
+}
+
+func (r *UserRepository) Unlock(userID uuid.UUID) error {
+	query := `UPDATE users SET failed_login_count = 0, locked_until = NULL, updated_at = $1 WHERE id = $2`
+	_, err := r.db.Exec(query, time.Now(), userID)
+	return err
+}
+
+func (r *UserRepository) Suspend(userID uuid.UUID, reason string, until *time.Time, suspendedBy uuid.UUID) error {
+	query := `
+		UPDATE users SET suspended_at = $1, suspended_until = $2, suspension_reason = $3,
+			   suspended_by = $4, updated_at = $1
+		WHERE id = $5
+	`
+	_, err := r.db.Exec(query, time.Now(), until, reason, suspendedBy, userID)
+	return err
+}
+
+func (r *UserRepository) ClearSuspension(userID uuid.UUID) error {
+	query := `
+		UPDATE users SET suspended_at = NULL, suspended_until = NULL, suspension_reason = NULL,
+			   suspended_by = NULL, updated_at = $1
+		WHERE id = $2
+	`
 	_, err := r.db.Exec(query, time.Now(), userID)
 	return err
 }
@@ -156,3 +244,87 @@ func (r *UserRepository) SetVerified(userID uuid.UUID) error {
 	_, err := r.db.Exec(query, time.Now(), userID)
 	return err
 }
+
+func (r *UserRepository) ScheduleDeletion(userID uuid.UUID, requestedAt, scheduledFor time.Time) error {
+	query := `
+		UPDATE users SET deletion_requested_at = $1, deletion_scheduled_for = $2, updated_at = $1
+		WHERE id = $3
+	`
+	_, err := r.db.Exec(query, requestedAt, scheduledFor, userID)
+	return err
+}
+
+func (r *UserRepository) CancelDeletion(userID uuid.UUID) error {
+	query := `
+		UPDATE users SET deletion_requested_at = NULL, deletion_scheduled_for = NULL, updated_at = $1
+		WHERE id = $2
+	`
+	_, err := r.db.Exec(query, time.Now(), userID)
+	return err
+}
+
+func (r *UserRepository) ListDueForErasure(now time.Time) ([]models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users
+		WHERE deletion_scheduled_for IS NOT NULL AND deletion_scheduled_for <= $1 AND erased_at IS NULL
+		ORDER BY deletion_scheduled_for`
+	return r.listUsers(query, now)
+}
+
+// Erase replaces every piece of personal data on the user row with a
+// placeholder. The row itself is kept so audit events stay attributable.
+func (r *UserRepository) Erase(userID uuid.UUID, placeholderEmail, placeholderHash string, erasedAt time.Time) error {
+	query := `
+		UPDATE users SET email = $1, password_hash = $2, display_name = 'Erased User',
+			   username = NULL, phone = NULL, metadata = '{"user": {}, "admin": {}}'::jsonb,
+			   is_active = false, last_login_at = NULL, failed_login_count = 0, locked_until = NULL,
+			   deletion_scheduled_for = NULL, erased_at = $3, updated_at = $3
+		WHERE id = $4
+	`
+	_, err := r.db.Exec(query, placeholderEmail, placeholderHash, erasedAt, userID)
+	return err
+}
+
+func (r *UserRepository) listUsers(query string, args ...interface{}) ([]models.User, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	return users, nil
+}
+
+func (r *UserRepository) ListDormantUnwarned(inactiveSince time.Time) ([]models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users
+		WHERE is_active = true AND erased_at IS NULL AND dormancy_warned_at IS NULL
+		  AND COALESCE(last_login_at, created_at) < $1
+		ORDER BY COALESCE(last_login_at, created_at)`
+	return r.listUsers(query, inactiveSince)
+}
+
+func (r *UserRepository) ListDormancyWarnedBefore(warnedBefore time.Time) ([]models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users
+		WHERE is_active = true AND dormancy_warned_at IS NOT NULL AND dormancy_warned_at <= $1
+		ORDER BY dormancy_warned_at`
+	return r.listUsers(query, warnedBefore)
+}
+
+func (r *UserRepository) MarkDormancyWarned(userID uuid.UUID, warnedAt time.Time) error {
+	query := `UPDATE users SET dormancy_warned_at = $1 WHERE id = $2`
+	_, err := r.db.Exec(query, warnedAt, userID)
+	return err
+}
+
+func (r *UserRepository) Deactivate(userID uuid.UUID) error {
+	query := `UPDATE users SET is_active = false, dormancy_warned_at = NULL, updated_at = $1 WHERE id = $2`
+	_, err := r.db.Exec(query, time.Now(), userID)
+	return err
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auth-service/internal/config"
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/auth-service/internal/utils"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDeletionAlreadyRequested = errors.New("account deletion already requested")
+	ErrNoDeletionPending        = errors.New("no account deletion pending")
+)
+
+type AccountService struct {
+	cfg            *config.Config
+	userRepo       *repository.UserRepository
+	roleRepo       *repository.RoleRepository
+	orgRepo        *repository.OrganizationRepository
+	tokenRepo      *repository.TokenRepository
+	securityStamps *SecurityStamps
+	auditRepo      *repository.AuditRepository
+	consentRepo    *repository.ConsentRepository
+	deviceRepo     *repository.DeviceRepository
+	auditService   *AuditService
+}
+
+func NewAccountService(
+	cfg *config.Config,
+	userRepo *repository.UserRepository,
+	roleRepo *repository.RoleRepository,
+	orgRepo *repository.OrganizationRepository,
+	tokenRepo *repository.TokenRepository,
+	securityStamps *SecurityStamps,
+	auditRepo *repository.AuditRepository,
+	consentRepo *repository.ConsentRepository,
+	deviceRepo *repository.DeviceRepository,
+	auditService *AuditService,
+) *AccountService {
+	return &AccountService{
+		cfg:            cfg,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		orgRepo:        orgRepo,
+		tokenRepo:      tokenRepo,
+		securityStamps: securityStamps,
+		auditRepo:      auditRepo,
+		consentRepo:    consentRepo,
+		deviceRepo:     deviceRepo,
+		auditService:   auditService,
+	}
+}
+
+func (s *AccountService) ExportData(userID uuid.UUID, ip, userAgent string) (*models.UserDataExport, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	memberships, err := listMemberships(s.orgRepo, s.roleRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.tokenRepo.ListActiveRefreshTokens(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	consents, err := s.consentRepo.ListUserConsents(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.auditRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogEvent(models.AuditEventDataExport, &userID, nil, ip, userAgent)
+
+	return &models.UserDataExport{
+		ExportedAt:    time.Now().UTC(),
+		User:          *user,
+		Organizations: memberships,
+		Sessions:      sessions,
+		Consents:      consents,
+		AuditEvents:   events,
+	}, nil
+}
+
+func (s *AccountService) RequestDeletion(userID uuid.UUID, password, ip, userAgent string) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if !utils.CheckPassword(password, user.PasswordHash) {
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.DeletionScheduledFor != nil {
+		return nil, ErrDeletionAlreadyRequested
+	}
+
+	if err := requireOtherAdminEverywhere(s.orgRepo, s.roleRepo, s.auditService, userID, userID, "request_deletion", ip, userAgent); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	scheduledFor := now.Add(s.cfg.AccountDeletionGracePeriod)
+	if err := s.userRepo.ScheduleDeletion(userID, now, scheduledFor); err != nil {
+		return nil, err
+	}
+	user.DeletionRequestedAt = &now
+	user.DeletionScheduledFor = &scheduledFor
+
+	s.auditService.LogEvent(models.AuditEventDeletionRequested, &userID, map[string]interface{}{
+		"scheduled_for": scheduledFor.UTC().Format(time.RFC3339),
+	}, ip, userAgent)
+
+	return user, nil
+}
+
+func (s *AccountService) CancelDeletion(userID uuid.UUID, ip, userAgent string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if user.DeletionScheduledFor == nil {
+		return ErrNoDeletionPending
+	}
+
+	if err := s.userRepo.CancelDeletion(userID); err != nil {
+		return err
+	}
+
+	s.auditService.LogEvent(models.AuditEventDeletionCancelled, &userID, nil, ip, userAgent)
+
+	return nil
+}
+
+func (s *AccountService) ProcessScheduledErasures() error {
+	users, err := s.userRepo.ListDueForErasure(time.Now())
+	if err != nil {
+		return err
+	}
+
+	// One account that cannot be erased must not hold up the others; the
+	// failures are reported together and retried on the next run.
+	var errs []error
+	for _, user := range users {
+		if err := s.erase(user); err != nil {
+			errs = append(errs, fmt.Errorf("erase user %s: %w", user.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *AccountService) erase(user models.User) error {
+	if err := s.tokenRepo.DeleteAllUserTokens(user.ID); err != nil {
+		return err
+	}
+
+	if err := s.deviceRepo.DeleteAllForUser(user.ID); err != nil {
+		return err
+	}
+
+	if err := s.roleRepo.RemoveAllUserRoles(user.ID); err != nil {
+		return err
+	}
+
+	if err := s.auditRepo.AnonymizeUserEvents(user.ID); err != nil {
+		return err
+	}
+
+	// The password hash is replaced with the hash of a random secret nobody
+	// knows, so the row can never be logged into again.
+	secret, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+	placeholderHash, err := utils.HashPassword(secret)
+	if err != nil {
+		return err
+	}
+
+	placeholderEmail := fmt.Sprintf("erased-%s@erased.invalid", user.ID)
+	if err := s.userRepo.Erase(user.ID, placeholderEmail, placeholderHash, time.Now()); err != nil {
+		return err
+	}
+
+	if err := s.securityStamps.Rotate(user.ID); err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{}
+	if user.DeletionRequestedAt != nil {
+		payload["requested_at"] = user.DeletionRequestedAt.UTC().Format(time.RFC3339)
+	}
+	s.auditService.LogEvent(models.AuditEventAccountErased, &user.ID, payload, "", "system")
+
+	return nil
+}
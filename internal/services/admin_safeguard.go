@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// adminRoleName is the system role that administers an organization.
+const adminRoleName = "admin"
+
+var ErrLastAdmin = errors.New("this would leave the organization without an active admin")
+
+// requireOtherAdmin refuses an operation that would stop userID from acting
+// as an admin of the organization when nobody else can. Refusals are audited
+// against the affected user.
+func requireOtherAdmin(roleRepo *repository.RoleRepository, auditService *AuditService, orgID, userID, actorID uuid.UUID, operation, ip, userAgent string) error {
+	isAdmin, err := roleRepo.UserHasRole(userID, orgID, adminRoleName)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return nil
+	}
+
+	return requireAdminOutside(roleRepo, auditService, orgID, []uuid.UUID{userID}, actorID, operation, ip, userAgent)
+}
+
+// requireAdminOutside refuses an operation that takes the admin role away
+// from all of userIDs at once, such as deleting a group that grants it, when
+// no active admin outside them would be left. Refusals are audited against
+// each of them; actorID is uuid.Nil for system jobs.
+func requireAdminOutside(roleRepo *repository.RoleRepository, auditService *AuditService, orgID uuid.UUID, userIDs []uuid.UUID, actorID uuid.UUID, operation, ip, userAgent string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	others, err := roleRepo.CountActiveHolders(orgID, adminRoleName, userIDs)
+	if err != nil {
+		return err
+	}
+	if others > 0 {
+		return nil
+	}
+
+	for _, userID := range userIDs {
+		userID := userID
+		payload := map[string]interface{}{
+			"operation":       operation,
+			"organization_id": orgID,
+		}
+		if actorID != uuid.Nil {
+			payload["actor"] = actorID.String()
+		}
+		auditService.LogEvent(models.AuditEventLastAdminProtected, &userID, payload, ip, userAgent)
+	}
+
+	return ErrLastAdmin
+}
+
+// requireOtherAdminEverywhere applies requireOtherAdmin to every
+// organization the user belongs to, for operations that affect the whole
+// account such as deletion or deactivation.
+func requireOtherAdminEverywhere(orgRepo *repository.OrganizationRepository, roleRepo *repository.RoleRepository, auditService *AuditService, userID, actorID uuid.UUID, operation, ip, userAgent string) error {
+	memberships, err := orgRepo.ListMemberships(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range memberships {
+		if err := requireOtherAdmin(roleRepo, auditService, m.Organization.ID, userID, actorID, operation, ip, userAgent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// roleGrants reports whether holding roleID implies holding the named role,
+// either because it is that role or because it inherits it.
+func roleGrants(roleRepo *repository.RoleRepository, roleID int, name string) (bool, error) {
+	target, err := roleRepo.GetByName(name)
+	if err != nil {
+		return false, nil
+	}
+
+	graph, err := roleRepo.InheritanceGraph()
+	if err != nil {
+		return false, err
+	}
+
+	return inheritsRole(graph, roleID, target.ID), nil
+}
+
+// anyRoleGrants is roleGrants for a set of roles held together, such as the
+// roles of a group.
+func anyRoleGrants(roleRepo *repository.RoleRepository, roleIDs []int, name string) (bool, error) {
+	for _, roleID := range roleIDs {
+		grants, err := roleGrants(roleRepo, roleID, name)
+		if err != nil || grants {
+			return grants, err
+		}
+	}
+	return false, nil
+}
+
+// inheritsRole reports whether roleID is targetID or reaches it in the
+// inheritance graph, which maps each role to the roles it inherits directly.
+// Cycles are tolerated.
+func inheritsRole(graph map[int][]int, roleID, targetID int) bool {
+	visited := make(map[int]bool)
+	stack := []int{roleID}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == targetID {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, graph[id]...)
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package services
+
+import "testing"
+
+func TestInheritsRole(t *testing.T) {
+	const (
+		admin = iota + 1
+		manager
+		lead
+		member
+		auditor
+		loopA
+		loopB
+	)
+
+	// lead -> manager -> admin, member on its own, auditor -> member, and a
+	// cycle between loopA and loopB that also reaches admin.
+	graph := map[int][]int{
+		manager: {admin},
+		lead:    {manager, member},
+		auditor: {member},
+		loopA:   {loopB},
+		loopB:   {loopA, admin},
+	}
+
+	tests := []struct {
+		name   string
+		roleID int
+		target int
+		want   bool
+	}{
+		{"role is the target", admin, admin, true},
+		{"direct inheritance", manager, admin, true},
+		{"transitive inheritance", lead, admin, true},
+		{"sibling branch", lead, member, true},
+		{"no inheritance", member, admin, false},
+		{"unrelated branch", auditor, admin, false},
+		{"inheritance is one-way", admin, manager, false},
+		{"role missing from graph", 99, admin, false},
+		{"cycle reaching the target", loopA, admin, true},
+		{"cycle not reaching the target", loopA, member, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inheritsRole(graph, tt.roleID, tt.target); got != tt.want {
+				t.Errorf("inheritsRole(%d, %d) = %v, want %v", tt.roleID, tt.target, got, tt.want)
+			}
+		})
+	}
+}
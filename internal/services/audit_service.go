@@ -10,11 +10,15 @@ import (
 )
 
 type AuditService struct {
-	auditRepo *repository.AuditRepository
+	auditRepo  *repository.AuditRepository
+	geoService *GeoService
 }
 
-func NewAuditService(auditRepo *repository.AuditRepository) *AuditService {
-	return &AuditService{auditRepo: auditRepo}
+func NewAuditService(auditRepo *repository.AuditRepository, geoService *GeoService) *AuditService {
+	return &AuditService{
+		auditRepo:  auditRepo,
+		geoService: geoService,
+	}
 }
 
 func (s *AuditService) LogEvent(eventType models.AuditEventType, userID *uuid.UUID, payload map[string]interface{}, ip, userAgent string) error {
@@ -27,6 +31,7 @@ func (s *AuditService) LogEvent(eventType models.AuditEventType, userID *uuid.UU
 		Payload:   string(payloadJSON),
 		IPAddress: ip,
 		UserAgent: userAgent,
+		Geo:       s.geoService.Lookup(ip),
 		CreatedAt: time.Now(),
 	}
 
@@ -3,6 +3,8 @@ package services
 import (
 	"database/sql"
 	"errors"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/auth-service/internal/config"
@@ -17,19 +19,32 @@ var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUserNotVerified    = errors.New("email not verified")
 	ErrAccountLocked      = errors.New("account is locked")
+	ErrAccountSuspended   = errors.New("account is suspended")
+	ErrAccountInactive    = errors.New("account is deactivated")
 	ErrDuplicateEmail     = errors.New("email already exists")
 	ErrInvalidToken       = errors.New("invalid or expired token")
 	ErrTokenRevoked       = errors.New("token has been revoked")
+	ErrSessionLimit       = errors.New("maximum number of active sessions reached")
+	ErrStepUpRequired     = errors.New("additional verification required")
+	ErrImpossibleTravel   = errors.New("login blocked from unusual location")
+	ErrNotOrgMember       = errors.New("not a member of this organization")
 )
 
 type AuthService struct {
-	cfg          *config.Config
-	userRepo     *repository.UserRepository
-	tokenRepo    *repository.TokenRepository
-	roleRepo     *repository.RoleRepository
-	emailService *EmailService
-	auditService *AuditService
-	jwtManager   *utils.JWTManager
+	cfg            *config.Config
+	userRepo       *repository.UserRepository
+	tokenRepo      *repository.TokenRepository
+	roleRepo       *repository.RoleRepository
+	orgRepo        *repository.OrganizationRepository
+	deviceRepo     *repository.DeviceRepository
+	geoService     *GeoService
+	emailService   *EmailService
+	auditService   *AuditService
+	consentService *ConsentService
+	throttler      *LoginThrottler
+	denylist       *TokenDenylist
+	securityStamps *SecurityStamps
+	jwtManager     *utils.JWTManager
 }
 
 func NewAuthService(
@@ -37,17 +52,31 @@ func NewAuthService(
 	userRepo *repository.UserRepository,
 	tokenRepo *repository.TokenRepository,
 	roleRepo *repository.RoleRepository,
+	orgRepo *repository.OrganizationRepository,
+	deviceRepo *repository.DeviceRepository,
+	geoService *GeoService,
 	emailService *EmailService,
 	auditService *AuditService,
+	consentService *ConsentService,
+	throttler *LoginThrottler,
+	denylist *TokenDenylist,
+	securityStamps *SecurityStamps,
 ) *AuthService {
 	return &AuthService{
-		cfg:          cfg,
-		userRepo:     userRepo,
-		tokenRepo:    tokenRepo,
-		roleRepo:     roleRepo,
-		emailService: emailService,
-		auditService: auditService,
-		jwtManager:   utils.NewJWTManager(cfg.JWTSigningKey, cfg.AccessTokenExpiry),
+		cfg:            cfg,
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		roleRepo:       roleRepo,
+		orgRepo:        orgRepo,
+		deviceRepo:     deviceRepo,
+		geoService:     geoService,
+		emailService:   emailService,
+		auditService:   auditService,
+		consentService: consentService,
+		throttler:      throttler,
+		denylist:       denylist,
+		securityStamps: securityStamps,
+		jwtManager:     utils.NewJWTManager(cfg.JWTSigningKey, cfg.AccessTokenExpiry),
 	}
 }
 
@@ -67,6 +96,21 @@ func (s *AuthService) Register(req models.RegisterRequest, ip, userAgent string)
 		return nil, ErrDuplicateEmail
 	}
 
+	username, err := normalizeUsername(s.userRepo, req.Username, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	phone, err := normalizePhone(s.userRepo, req.Phone, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	consentIDs, err := s.consentService.ResolveRegistration(req.TermsVersion, req.PrivacyVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	passwordHash, err := utils.HashPassword(req.Password)
 	if err != nil {
 		return nil, err
@@ -75,6 +119,8 @@ func (s *AuthService) Register(req models.RegisterRequest, ip, userAgent string)
 	user := &models.User{
 		ID:           uuid.New(),
 		Email:        email,
+		Username:     username,
+		Phone:        phone,
 		PasswordHash: passwordHash,
 		DisplayName:  req.DisplayName,
 		IsActive:     true,
@@ -87,9 +133,17 @@ func (s *AuthService) Register(req models.RegisterRequest, ip, userAgent string)
 		return nil, err
 	}
 
+	if _, err := s.orgRepo.AddMember(models.DefaultOrganizationID, user.ID); err != nil {
+		return nil, err
+	}
+
 	defaultRole, _ := s.roleRepo.GetByName("user")
 	if defaultRole != nil {
-		s.roleRepo.AssignRoleToUser(user.ID, defaultRole.ID, user.ID)
+		s.roleRepo.AssignRoleToUser(user.ID, models.DefaultOrganizationID, defaultRole.ID, user.ID, nil, nil)
+	}
+
+	if err := s.consentService.Accept(user.ID, consentIDs, ip, userAgent); err != nil {
+		return nil, err
 	}
 
 	token, _ := utils.GenerateRandomToken(32)
@@ -137,17 +191,38 @@ func (s *AuthService) VerifyEmail(tokenStr, ip, userAgent string) error {
 }
 
 func (s *AuthService) Login(req models.LoginRequest, ip, userAgent string) (*models.AuthResponse, error) {
-	email := utils.SanitizeEmail(req.Email)
+	identifier := req.Identifier
+	if identifier == "" {
+		identifier = req.Email
+	}
 
-	user, err := s.userRepo.GetByEmail(email)
+	user, normalized, err := lookupByIdentifier(s.userRepo, identifier)
 	if err != nil {
 		if err == sql.ErrNoRows {
+			if wait := s.throttler.Check(normalized, ip); wait > 0 {
+				return nil, &LoginThrottledError{RetryAfter: wait}
+			}
+			s.throttler.RecordFailure(normalized, ip)
 			return nil, ErrInvalidCredentials
 		}
 		return nil, err
 	}
 
-	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+	accountKey := user.ID.String()
+	if wait := s.throttler.Check(accountKey, ip); wait > 0 {
+		s.auditService.LogEvent(models.AuditEventLoginFailed, &user.ID, map[string]interface{}{
+			"reason":      "throttled",
+			"retry_after": int(wait.Seconds()),
+		}, ip, userAgent)
+		return nil, &LoginThrottledError{RetryAfter: wait}
+	}
+
+	// The account-wide lock can be triggered by anyone who knows a login
+	// identifier, so devices the user has already signed in from are exempt.
+	fingerprint, device, ipPrefix := utils.DeviceFingerprint(userAgent, ip)
+	knownDevice, _ := s.deviceRepo.IsKnown(user.ID, fingerprint)
+
+	if !knownDevice && user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
 		s.auditService.LogEvent(models.AuditEventLoginFailed, &user.ID, map[string]interface{}{
 			"reason": "account_locked",
 		}, ip, userAgent)
@@ -156,62 +231,362 @@ func (s *AuthService) Login(req models.LoginRequest, ip, userAgent string) (*mod
 
 	if !utils.CheckPassword(req.Password, user.PasswordHash) {
 		s.userRepo.IncrementFailedLogin(user.ID)
+		s.throttler.RecordFailure(accountKey, ip)
 
-		if user.FailedLoginCount+1 >= s.cfg.MaxFailedLogins {
+		if s.cfg.MaxFailedLogins > 0 && user.FailedLoginCount+1 >= s.cfg.MaxFailedLogins {
 			lockUntil := time.Now().Add(s.cfg.LockDuration)
 			s.userRepo.LockAccount(user.ID, lockUntil)
 		}
 
 		s.auditService.LogEvent(models.AuditEventLoginFailed, &user.ID, map[string]interface{}{
-			"reason": "invalid_password",
+			"reason":       "invalid_password",
+			"known_device": knownDevice,
 		}, ip, userAgent)
 		return nil, ErrInvalidCredentials
 	}
 
+	s.throttler.RecordSuccess(accountKey, ip)
+
 	if !user.IsVerified {
 		return nil, ErrUserNotVerified
 	}
 
 	if !user.IsActive {
-		return nil, errors.New("account is deactivated")
+		return nil, ErrAccountInactive
+	}
+
+	if user.IsSuspended(time.Now()) {
+		s.auditService.LogEvent(models.AuditEventLoginFailed, &user.ID, map[string]interface{}{
+			"reason": "account_suspended",
+		}, ip, userAgent)
+		return nil, ErrAccountSuspended
+	}
+
+	geo := s.geoService.Lookup(ip)
+	if err := s.checkTravel(user, geo, req.StepUpCode, ip, userAgent); err != nil {
+		return nil, err
+	}
+
+	orgID, err := s.resolveOrganization(user.ID, req.OrganizationID)
+	if err != nil {
+		return nil, err
 	}
 
 	s.userRepo.ResetFailedLogin(user.ID)
 
-	roles, _ := s.roleRepo.GetUserRoles(user.ID)
+	roles, _ := s.roleRepo.GetUserRoles(user.ID, orgID)
 	roleNames := make([]string, len(roles))
 	for i, r := range roles {
 		roleNames[i] = r.Name
 	}
 
-	accessToken, err := s.jwtManager.GenerateToken(user.ID, user.Email, roleNames)
+	if err := s.enforceSessionLimit(user.ID, roles, ip, userAgent); err != nil {
+		return nil, err
+	}
+
+	sessionID := uuid.New()
+	accessToken, err := s.jwtManager.GenerateToken(user.ID, user.Email, roleNames, sessionID, orgID, user.SecurityStamp)
 	if err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
 	refreshTokenStr, _ := utils.GenerateRandomToken(32)
 	refreshToken := &models.RefreshToken{
-		ID:        uuid.New(),
-		UserID:    user.ID,
-		TokenHash: utils.HashToken(refreshTokenStr),
-		IssuedAt:  time.Now(),
-		ExpiresAt: time.Now().Add(s.cfg.RefreshTokenExpiry),
-		Revoked:   false,
-		UserAgent: userAgent,
-		IPAddress: ip,
+		ID:               uuid.New(),
+		UserID:           user.ID,
+		SessionID:        sessionID,
+		OrganizationID:   orgID,
+		TokenHash:        utils.HashToken(refreshTokenStr),
+		IssuedAt:         now,
+		SessionStartedAt: now,
+		ExpiresAt:        now.Add(s.cfg.RefreshTokenExpiry),
+		Revoked:          false,
+		UserAgent:        userAgent,
+		IPAddress:        ip,
+		Geo:              geo,
 	}
 
 	if err := s.tokenRepo.CreateRefreshToken(refreshToken); err != nil {
 		return nil, err
 	}
 
-	s.auditService.LogEvent(models.AuditEventLoginSuccess, &user.ID, nil, ip, userAgent)
+	s.auditService.LogEvent(models.AuditEventLoginSuccess, &user.ID, map[string]interface{}{
+		"session_id":      sessionID,
+		"organization_id": orgID,
+	}, ip, userAgent)
 
-	return &models.AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshTokenStr,
-		ExpiresIn:    int64(s.cfg.AccessTokenExpiry.Seconds()),
-	}, nil
+	s.rememberDevice(user, sessionID, knownDevice, &models.KnownDevice{
+		ID:           uuid.New(),
+		UserID:       user.ID,
+		Fingerprint:  fingerprint,
+		Browser:      device.Browser,
+		OS:           device.OS,
+		IPPrefix:     ipPrefix,
+		FirstSession: sessionID,
+	}, ip, userAgent)
+
+	response := &models.AuthResponse{
+		AccessToken:    accessToken,
+		RefreshToken:   refreshTokenStr,
+		ExpiresIn:      int64(s.cfg.AccessTokenExpiry.Seconds()),
+		OrganizationID: orgID,
+	}
+	s.addPendingConsents(response, user.ID)
+
+	return response, nil
+}
+
+// resolveOrganization returns the organization a login acts in: the one the
+// client asked for, which the user must belong to, or their default one.
+func (s *AuthService) resolveOrganization(userID, requested uuid.UUID) (uuid.UUID, error) {
+	if requested == uuid.Nil {
+		orgID, err := s.orgRepo.DefaultForUser(userID)
+		if err == sql.ErrNoRows {
+			return uuid.Nil, ErrNotOrgMember
+		}
+		return orgID, err
+	}
+
+	member, err := s.orgRepo.IsMember(requested, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !member {
+		return uuid.Nil, ErrNotOrgMember
+	}
+	return requested, nil
+}
+
+// checkTravel flags logins whose distance from the previous login could not
+// have been covered in the time between them, and applies the configured
+// action. With step_up the user is emailed a code and has to repeat the
+// login with it.
+func (s *AuthService) checkTravel(user *models.User, geo *models.GeoLocation, stepUpCode, ip, userAgent string) error {
+	check := s.geoService.CheckTravel(user.ID, geo, time.Now())
+	if check == nil || !check.Implausible {
+		return nil
+	}
+
+	action := s.cfg.ImpossibleTravelAction
+	if action == "step_up" && stepUpCode != "" && s.consumeStepUpCode(user.ID, stepUpCode) {
+		action = "step_up_passed"
+	}
+
+	s.auditService.LogEvent(models.AuditEventImpossibleTravel, &user.ID, map[string]interface{}{
+		"from_country": check.From.Country,
+		"from_city":    check.From.City,
+		"to_country":   geo.Country,
+		"to_city":      geo.City,
+		"distance_km":  int(check.DistanceKm),
+		"speed_kmh":    int(check.SpeedKmh),
+		"action":       action,
+	}, ip, userAgent)
+
+	switch action {
+	case "block":
+		return ErrImpossibleTravel
+	case "step_up":
+		code, _ := utils.GenerateRandomToken(6)
+		emailToken := &models.EmailToken{
+			ID:        uuid.New(),
+			UserID:    user.ID,
+			TokenHash: utils.HashToken(code),
+			Type:      models.EmailTokenTypeStepUp,
+			ExpiresAt: time.Now().Add(10 * time.Minute),
+			Used:      false,
+		}
+		if err := s.tokenRepo.CreateEmailToken(emailToken); err != nil {
+			return err
+		}
+
+		location := strings.Trim(geo.City+", "+geo.Country, ", ")
+		go s.emailService.SendStepUpCodeEmail(user.Email, user.DisplayName, code, location)
+		return ErrStepUpRequired
+	}
+
+	return nil
+}
+
+func (s *AuthService) consumeStepUpCode(userID uuid.UUID, code string) bool {
+	emailToken, err := s.tokenRepo.GetEmailTokenByHash(utils.HashToken(code), models.EmailTokenTypeStepUp)
+	if err != nil || emailToken.UserID != userID {
+		return false
+	}
+
+	if emailToken.Used || time.Now().After(emailToken.ExpiresAt) {
+		return false
+	}
+
+	return s.tokenRepo.MarkEmailTokenUsed(emailToken.ID) == nil
+}
+
+// sessionLimit returns the maximum number of concurrent sessions for a user
+// with the given roles, or 0 for no limit. Role overrides take precedence
+// over the global default, and the most generous role wins.
+func (s *AuthService) sessionLimit(roles []models.Role) int {
+	limit := -1
+	for _, role := range roles {
+		if role.MaxSessions == nil {
+			continue
+		}
+		if *role.MaxSessions == 0 {
+			return 0
+		}
+		if *role.MaxSessions > limit {
+			limit = *role.MaxSessions
+		}
+	}
+	if limit < 0 {
+		return s.cfg.MaxSessionsPerUser
+	}
+	return limit
+}
+
+// enforceSessionLimit makes room for one more session, either by refusing
+// the login or by ending the oldest sessions, depending on the policy.
+func (s *AuthService) enforceSessionLimit(userID uuid.UUID, roles []models.Role, ip, userAgent string) error {
+	limit := s.sessionLimit(roles)
+	if limit <= 0 {
+		return nil
+	}
+
+	sessions, err := s.tokenRepo.ListActiveRefreshTokens(userID)
+	if err != nil {
+		return err
+	}
+	if len(sessions) < limit {
+		return nil
+	}
+
+	if s.cfg.SessionLimitPolicy == "reject" {
+		s.auditService.LogEvent(models.AuditEventLoginFailed, &userID, map[string]interface{}{
+			"reason": "session_limit",
+			"limit":  limit,
+		}, ip, userAgent)
+		return ErrSessionLimit
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].SessionStartedAt.Before(sessions[j].SessionStartedAt)
+	})
+
+	for _, session := range sessions[:len(sessions)-limit+1] {
+		if _, err := s.tokenRepo.RevokeSession(userID, session.SessionID); err != nil {
+			return err
+		}
+		s.denylist.RevokeSession(session.SessionID)
+
+		s.auditService.LogEvent(models.AuditEventSessionEvicted, &userID, map[string]interface{}{
+			"session_id": session.SessionID,
+			"limit":      limit,
+		}, ip, userAgent)
+	}
+
+	return nil
+}
+
+// rememberDevice records the login device and emails the user when it is
+// one they have not used before. The first device on an account is not
+// announced since it is almost always the one they registered from.
+func (s *AuthService) rememberDevice(user *models.User, sessionID uuid.UUID, known bool, device *models.KnownDevice, ip, userAgent string) {
+	if known {
+		s.deviceRepo.Upsert(device)
+		return
+	}
+
+	count, err := s.deviceRepo.CountForUser(user.ID)
+	if err != nil {
+		return
+	}
+	if err := s.deviceRepo.Upsert(device); err != nil || count == 0 {
+		return
+	}
+
+	token, _ := utils.GenerateRandomToken(32)
+	emailToken := &models.EmailToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		Type:      models.EmailTokenTypeNotMe,
+		ExpiresAt: time.Now().Add(s.cfg.NewDeviceAlertExpiry),
+		Used:      false,
+		SessionID: &sessionID,
+	}
+	if err := s.tokenRepo.CreateEmailToken(emailToken); err != nil {
+		return
+	}
+
+	description := strings.TrimSpace(device.Browser + " on " + device.OS)
+	if device.Browser == "" && device.OS == "" {
+		description = "Unknown device"
+	}
+	go s.emailService.SendNewDeviceEmail(user.Email, user.DisplayName, description, ip, time.Now(), token)
+
+	s.auditService.LogEvent(models.AuditEventNewDeviceLogin, &user.ID, map[string]interface{}{
+		"session_id": sessionID,
+		"browser":    device.Browser,
+		"os":         device.OS,
+		"ip_prefix":  device.IPPrefix,
+	}, ip, userAgent)
+}
+
+// DisownLogin handles the "this wasn't me" link from a new-device email:
+// it ends the session, forgets the device and starts a password reset.
+func (s *AuthService) DisownLogin(tokenStr, ip, userAgent string) error {
+	tokenHash := utils.HashToken(tokenStr)
+	emailToken, err := s.tokenRepo.GetEmailTokenByHash(tokenHash, models.EmailTokenTypeNotMe)
+	if err != nil || emailToken == nil {
+		return ErrInvalidToken
+	}
+
+	if emailToken.Used || time.Now().After(emailToken.ExpiresAt) {
+		return ErrInvalidToken
+	}
+
+	if err := s.tokenRepo.MarkEmailTokenUsed(emailToken.ID); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(emailToken.UserID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	payload := map[string]interface{}{}
+	if emailToken.SessionID != nil {
+		sessionID := *emailToken.SessionID
+		if _, err := s.tokenRepo.RevokeSession(user.ID, sessionID); err != nil {
+			return err
+		}
+		s.denylist.RevokeSession(sessionID)
+		s.deviceRepo.ForgetBySession(user.ID, sessionID)
+		payload["session_id"] = sessionID
+	}
+
+	resetToken, _ := utils.GenerateRandomToken(32)
+	s.tokenRepo.CreateEmailToken(&models.EmailToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(resetToken),
+		Type:      models.EmailTokenTypeReset,
+		ExpiresAt: time.Now().Add(time.Hour),
+		Used:      false,
+	})
+
+	go s.emailService.SendPasswordResetEmail(user.Email, user.DisplayName, resetToken)
+
+	s.auditService.LogEvent(models.AuditEventLoginDisowned, &user.ID, payload, ip, userAgent)
+
+	return nil
+}
+
+func (s *AuthService) addPendingConsents(response *models.AuthResponse, userID uuid.UUID) {
+	pending, _ := s.consentService.PendingForUser(userID)
+	if len(pending) > 0 {
+		response.ConsentRequired = true
+		response.PendingConsents = pending
+	}
 }
 
 func (s *AuthService) RefreshToken(tokenStr, ip, userAgent string) (*models.AuthResponse, error) {
@@ -237,46 +612,149 @@ func (s *AuthService) RefreshToken(tokenStr, ip, userAgent string) (*models.Auth
 		return nil, ErrUserNotFound
 	}
 
-	roles, _ := s.roleRepo.GetUserRoles(user.ID)
+	if !user.IsActive {
+		return nil, ErrAccountInactive
+	}
+
+	if user.IsSuspended(time.Now()) {
+		return nil, ErrAccountSuspended
+	}
+
+	// The session ends if the user has since been removed from its organization
+	orgID := oldToken.OrganizationID
+	if member, err := s.orgRepo.IsMember(orgID, user.ID); err != nil || !member {
+		return nil, ErrNotOrgMember
+	}
+
+	roles, _ := s.roleRepo.GetUserRoles(user.ID, orgID)
 	roleNames := make([]string, len(roles))
 	for i, r := range roles {
 		roleNames[i] = r.Name
 	}
 
-	accessToken, err := s.jwtManager.GenerateToken(user.ID, user.Email, roleNames)
+	accessToken, err := s.jwtManager.GenerateToken(user.ID, user.Email, roleNames, oldToken.SessionID, orgID, user.SecurityStamp)
 	if err != nil {
 		return nil, err
 	}
 
 	newRefreshTokenStr, _ := utils.GenerateRandomToken(32)
 	newRefreshToken := &models.RefreshToken{
-		ID:        uuid.New(),
-		UserID:    user.ID,
-		TokenHash: utils.HashToken(newRefreshTokenStr),
-		IssuedAt:  time.Now(),
-		ExpiresAt: time.Now().Add(s.cfg.RefreshTokenExpiry),
-		Revoked:   false,
-		UserAgent: userAgent,
-		IPAddress: ip,
+		ID:               uuid.New(),
+		UserID:           user.ID,
+		SessionID:        oldToken.SessionID,
+		OrganizationID:   orgID,
+		TokenHash:        utils.HashToken(newRefreshTokenStr),
+		IssuedAt:         time.Now(),
+		SessionStartedAt: oldToken.SessionStartedAt,
+		ExpiresAt:        time.Now().Add(s.cfg.RefreshTokenExpiry),
+		Revoked:          false,
+		UserAgent:        userAgent,
+		IPAddress:        ip,
+		Geo:              s.geoService.Lookup(ip),
 	}
 
 	if err := s.tokenRepo.CreateRefreshToken(newRefreshToken); err != nil {
 		return nil, err
 	}
 
+	response := &models.AuthResponse{
+		AccessToken:    accessToken,
+		RefreshToken:   newRefreshTokenStr,
+		ExpiresIn:      int64(s.cfg.AccessTokenExpiry.Seconds()),
+		OrganizationID: orgID,
+	}
+	s.addPendingConsents(response, user.ID)
+
+	return response, nil
+}
+
+// SwitchOrganization moves the current session to another organization the
+// user belongs to and returns an access token carrying that organization's
+// roles. The refresh token stays valid and follows the session; the access
+// token tokenID it replaces is revoked.
+func (s *AuthService) SwitchOrganization(userID, sessionID uuid.UUID, tokenID string, orgID uuid.UUID, ip, userAgent string) (*models.AuthResponse, error) {
+	if sessionID == uuid.Nil {
+		return nil, ErrInvalidToken
+	}
+
+	member, err := s.orgRepo.IsMember(orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrNotOrgMember
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if !user.IsActive {
+		return nil, ErrAccountInactive
+	}
+
+	if user.IsSuspended(time.Now()) {
+		return nil, ErrAccountSuspended
+	}
+
+	moved, err := s.tokenRepo.SetSessionOrganization(userID, sessionID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !moved {
+		return nil, ErrSessionNotFound
+	}
+
+	roles, _ := s.roleRepo.GetUserRoles(userID, orgID)
+	roleNames := make([]string, len(roles))
+	for i, r := range roles {
+		roleNames[i] = r.Name
+	}
+
+	accessToken, err := s.jwtManager.GenerateToken(user.ID, user.Email, roleNames, sessionID, orgID, user.SecurityStamp)
+	if err != nil {
+		return nil, err
+	}
+
+	// The old token still names the previous organization.
+	if err := s.denylist.RevokeToken(tokenID); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogEvent(models.AuditEventOrganizationSwitched, &userID, map[string]interface{}{
+		"session_id":      sessionID,
+		"organization_id": orgID,
+	}, ip, userAgent)
+
 	return &models.AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: newRefreshTokenStr,
-		ExpiresIn:    int64(s.cfg.AccessTokenExpiry.Seconds()),
+		AccessToken:    accessToken,
+		ExpiresIn:      int64(s.cfg.AccessTokenExpiry.Seconds()),
+		OrganizationID: orgID,
 	}, nil
 }
 
-func (s *AuthService) Logout(userID uuid.UUID, ip, userAgent string) error {
-	if err := s.tokenRepo.RevokeAllUserTokens(userID); err != nil {
-		return err
+// Logout ends the session the access token belongs to, or every session
+// of the user when all is set. Tokens issued before sessions were tracked
+// carry no session ID and fall back to revoking everything.
+func (s *AuthService) Logout(userID, sessionID uuid.UUID, all bool, ip, userAgent string) error {
+	if all || sessionID == uuid.Nil {
+		if err := s.tokenRepo.RevokeAllUserTokens(userID); err != nil {
+			return err
+		}
+		s.denylist.RevokeUser(userID)
+		all = true
+	} else {
+		if _, err := s.tokenRepo.RevokeSession(userID, sessionID); err != nil {
+			return err
+		}
+		s.denylist.RevokeSession(sessionID)
 	}
 
-	s.auditService.LogEvent(models.AuditEventLogout, &userID, nil, ip, userAgent)
+	s.auditService.LogEvent(models.AuditEventLogout, &userID, map[string]interface{}{
+		"session_id": sessionID,
+		"all":        all,
+	}, ip, userAgent)
 
 	return nil
 }
@@ -330,6 +808,7 @@ func (s *AuthService) ResetPassword(tokenStr, newPassword, ip, userAgent string)
 
 	s.tokenRepo.MarkEmailTokenUsed(emailToken.ID)
 	s.tokenRepo.RevokeAllUserTokens(emailToken.UserID)
+	s.securityStamps.Rotate(emailToken.UserID)
 
 	s.auditService.LogEvent(models.AuditEventPasswordReset, &emailToken.UserID, nil, ip, userAgent)
 
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPermissionNotFound = errors.New("permission not found")
+	ErrPermissionExists   = errors.New("permission already exists")
+	ErrInvalidPermission  = errors.New("permission names must look like resource:action, e.g. users:read")
+	ErrRoleNotFound       = errors.New("role not found")
+)
+
+var permissionNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]*(:[a-z][a-z0-9_-]*)+$`)
+
+type AuthzService struct {
+	permissionRepo *repository.PermissionRepository
+	roleRepo       *repository.RoleRepository
+	userRepo       *repository.UserRepository
+	policyEngine   *PolicyEngine
+	auditService   *AuditService
+}
+
+func NewAuthzService(
+	permissionRepo *repository.PermissionRepository,
+	roleRepo *repository.RoleRepository,
+	userRepo *repository.UserRepository,
+	policyEngine *PolicyEngine,
+	auditService *AuditService,
+) *AuthzService {
+	return &AuthzService{
+		permissionRepo: permissionRepo,
+		roleRepo:       roleRepo,
+		userRepo:       userRepo,
+		policyEngine:   policyEngine,
+		auditService:   auditService,
+	}
+}
+
+// PolicyResource identifies what an action is performed on. Attributes are
+// merged into the resource seen by policies; for "user" resources the
+// target user's attributes are loaded automatically.
+type PolicyResource struct {
+	Type       string
+	ID         string
+	Attributes map[string]interface{}
+}
+
+type AccessRequest struct {
+	UserID         uuid.UUID
+	OrganizationID uuid.UUID
+	Action         string
+	Resource       PolicyResource
+	IP             string
+}
+
+// AccessDecision records why access was granted or refused: Policy is set
+// when a policy decided, otherwise the outcome came from RBAC permissions.
+type AccessDecision struct {
+	Allowed bool   `json:"allowed"`
+	Policy  string `json:"policy,omitempty"`
+}
+
+func (s *AuthzService) EffectivePermissions(userID, orgID uuid.UUID) ([]string, error) {
+	return s.permissionRepo.GetUserPermissions(userID, orgID)
+}
+
+// HasPermissions reports whether the user holds every one of the given
+// permissions through at least one of their roles in the organization.
+func (s *AuthzService) HasPermissions(userID, orgID uuid.UUID, required ...string) (bool, error) {
+	granted, err := s.EffectivePermissions(userID, orgID)
+	if err != nil {
+		return false, err
+	}
+
+	set := make(map[string]bool, len(granted))
+	for _, p := range granted {
+		set[p] = true
+	}
+	for _, p := range required {
+		if !set[p] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Authorize evaluates the attribute-based policies for the action. A deny
+// policy always wins and an allow policy grants access outright; when no
+// policy matches, the caller needs the permission named by the action.
+func (s *AuthzService) Authorize(req AccessRequest) (*AccessDecision, error) {
+	subject, err := s.policySubject(req.UserID, req.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	input := PolicyInput{
+		Subject:  subject,
+		Resource: s.policyResource(req.Resource),
+		Env: map[string]interface{}{
+			"time": time.Now().UTC(),
+			"ip":   req.IP,
+		},
+	}
+
+	decision := s.policyEngine.Evaluate(req.Action, input)
+	switch decision.Effect {
+	case PolicyEffectDeny:
+		return &AccessDecision{Allowed: false, Policy: decision.Policy}, nil
+	case PolicyEffectAllow:
+		return &AccessDecision{Allowed: true, Policy: decision.Policy}, nil
+	}
+
+	allowed, err := s.HasPermissions(req.UserID, req.OrganizationID, req.Action)
+	if err != nil {
+		return nil, err
+	}
+	return &AccessDecision{Allowed: allowed}, nil
+}
+
+func (s *AuthzService) policySubject(userID, orgID uuid.UUID) (map[string]interface{}, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	roles, err := s.roleRepo.GetUserRoles(userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	roleNames := make([]string, len(roles))
+	for i, role := range roles {
+		roleNames[i] = role.Name
+	}
+
+	permissions, err := s.EffectivePermissions(userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := userAttributes(user)
+	subject["roles"] = roleNames
+	subject["permissions"] = permissions
+	subject["organization_id"] = orgID.String()
+	return subject, nil
+}
+
+func (s *AuthzService) policyResource(resource PolicyResource) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	if resource.Type == "user" {
+		if id, err := uuid.Parse(resource.ID); err == nil {
+			if user, err := s.userRepo.GetByID(id); err == nil {
+				attrs = userAttributes(user)
+			}
+		}
+	}
+
+	for k, v := range resource.Attributes {
+		attrs[k] = v
+	}
+	attrs["type"] = resource.Type
+	attrs["id"] = resource.ID
+	return attrs
+}
+
+// userAttributes exposes a user to policies. Only admin-managed metadata is
+// offered as "attributes" since users can edit their own metadata freely.
+func userAttributes(user *models.User) map[string]interface{} {
+	attributes := user.Metadata.Admin
+	if attributes == nil {
+		attributes = map[string]interface{}{}
+	}
+	userAttrs := user.Metadata.User
+	if userAttrs == nil {
+		userAttrs = map[string]interface{}{}
+	}
+
+	return map[string]interface{}{
+		"id":              user.ID.String(),
+		"email":           user.Email,
+		"is_active":       user.IsActive,
+		"is_verified":     user.IsVerified,
+		"attributes":      attributes,
+		"user_attributes": userAttrs,
+	}
+}
+
+func (s *AuthzService) ListPermissions() ([]models.Permission, error) {
+	return s.permissionRepo.List()
+}
+
+func (s *AuthzService) CreatePermission(req models.CreatePermissionRequest, actorID uuid.UUID, ip, userAgent string) (*models.Permission, error) {
+	if !permissionNameRegex.MatchString(req.Name) {
+		return nil, ErrInvalidPermission
+	}
+
+	if existing, _ := s.permissionRepo.GetByName(req.Name); existing != nil {
+		return nil, ErrPermissionExists
+	}
+
+	permission := &models.Permission{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := s.permissionRepo.Create(permission); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogEvent(models.AuditEventPermissionChange, &actorID, map[string]interface{}{
+		"action":     "created",
+		"permission": permission.Name,
+	}, ip, userAgent)
+
+	return permission, nil
+}
+
+func (s *AuthzService) DeletePermission(id int, actorID uuid.UUID, ip, userAgent string) error {
+	permission, err := s.permissionRepo.GetByID(id)
+	if err != nil {
+		return ErrPermissionNotFound
+	}
+
+	if err := s.permissionRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.auditService.LogEvent(models.AuditEventPermissionChange, &actorID, map[string]interface{}{
+		"action":     "deleted",
+		"permission": permission.Name,
+	}, ip, userAgent)
+
+	return nil
+}
+
+func (s *AuthzService) ListRolePermissions(roleID int) ([]models.Permission, error) {
+	if _, err := s.roleRepo.GetByID(roleID); err != nil {
+		return nil, ErrRoleNotFound
+	}
+	return s.permissionRepo.ListByRole(roleID)
+}
+
+func (s *AuthzService) GrantPermission(roleID int, name string, actorID uuid.UUID, ip, userAgent string) error {
+	role, permission, err := s.lookupGrant(roleID, name)
+	if err != nil {
+		return err
+	}
+
+	if err := s.permissionRepo.GrantToRole(role.ID, permission.ID); err != nil {
+		return err
+	}
+
+	s.auditService.LogEvent(models.AuditEventPermissionChange, &actorID, map[string]interface{}{
+		"action":     "granted",
+		"role":       role.Name,
+		"permission": permission.Name,
+	}, ip, userAgent)
+
+	return nil
+}
+
+func (s *AuthzService) RevokePermission(roleID int, name string, actorID uuid.UUID, ip, userAgent string) error {
+	role, permission, err := s.lookupGrant(roleID, name)
+	if err != nil {
+		return err
+	}
+
+	if err := s.permissionRepo.RevokeFromRole(role.ID, permission.ID); err != nil {
+		return err
+	}
+
+	s.auditService.LogEvent(models.AuditEventPermissionChange, &actorID, map[string]interface{}{
+		"action":     "revoked",
+		"role":       role.Name,
+		"permission": permission.Name,
+	}, ip, userAgent)
+
+	return nil
+}
+
+func (s *AuthzService) lookupGrant(roleID int, name string) (*models.Role, *models.Permission, error) {
+	role, err := s.roleRepo.GetByID(roleID)
+	if err != nil {
+		return nil, nil, ErrRoleNotFound
+	}
+
+	permission, err := s.permissionRepo.GetByName(name)
+	if err != nil {
+		return nil, nil, ErrPermissionNotFound
+	}
+
+	return role, permission, nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrConsentRequired        = errors.New("acceptance of the current terms of service and privacy policy is required")
+	ErrConsentVersionMismatch = errors.New("accepted document version is not the current version")
+	ErrConsentDocumentExists  = errors.New("document version already published")
+	ErrConsentDocumentInvalid = errors.New("document is not a current terms of service or privacy policy")
+)
+
+type ConsentService struct {
+	consentRepo  *repository.ConsentRepository
+	auditService *AuditService
+}
+
+func NewConsentService(consentRepo *repository.ConsentRepository, auditService *AuditService) *ConsentService {
+	return &ConsentService{
+		consentRepo:  consentRepo,
+		auditService: auditService,
+	}
+}
+
+func (s *ConsentService) CurrentDocuments() ([]models.ConsentDocument, error) {
+	return s.consentRepo.ListCurrentDocuments()
+}
+
+func (s *ConsentService) ListDocuments() ([]models.ConsentDocument, error) {
+	return s.consentRepo.ListDocuments()
+}
+
+func (s *ConsentService) PublishDocument(req models.PublishConsentDocumentRequest, publishedBy uuid.UUID, ip, userAgent string) (*models.ConsentDocument, error) {
+	if req.Type != models.ConsentDocumentTerms && req.Type != models.ConsentDocumentPrivacy {
+		return nil, errors.New("type must be 'terms' or 'privacy'")
+	}
+
+	existing, _ := s.consentRepo.GetDocumentByVersion(req.Type, req.Version)
+	if existing != nil {
+		return nil, ErrConsentDocumentExists
+	}
+
+	doc := &models.ConsentDocument{
+		Type:        req.Type,
+		Version:     req.Version,
+		URL:         req.URL,
+		PublishedAt: time.Now(),
+		PublishedBy: &publishedBy,
+	}
+
+	if err := s.consentRepo.CreateDocument(doc); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogEvent(models.AuditEventConsentPublished, &publishedBy, map[string]interface{}{
+		"document_id": doc.ID,
+		"type":        doc.Type,
+		"version":     doc.Version,
+	}, ip, userAgent)
+
+	return doc, nil
+}
+
+// ResolveRegistration checks that the versions a registering user agreed to
+// are the ones currently published and returns the matching document IDs.
+// Document types with nothing published are not required.
+func (s *ConsentService) ResolveRegistration(termsVersion, privacyVersion string) ([]int, error) {
+	current, err := s.consentRepo.ListCurrentDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(current))
+	for _, doc := range current {
+		accepted := termsVersion
+		if doc.Type == models.ConsentDocumentPrivacy {
+			accepted = privacyVersion
+		}
+
+		if accepted == "" {
+			return nil, ErrConsentRequired
+		}
+		if accepted != doc.Version {
+			return nil, ErrConsentVersionMismatch
+		}
+		ids = append(ids, doc.ID)
+	}
+
+	return ids, nil
+}
+
+func (s *ConsentService) Accept(userID uuid.UUID, documentIDs []int, ip, userAgent string) error {
+	current, err := s.consentRepo.ListCurrentDocuments()
+	if err != nil {
+		return err
+	}
+
+	currentByID := make(map[int]models.ConsentDocument, len(current))
+	for _, doc := range current {
+		currentByID[doc.ID] = doc
+	}
+
+	for _, id := range documentIDs {
+		if _, ok := currentByID[id]; !ok {
+			return ErrConsentDocumentInvalid
+		}
+	}
+
+	for _, id := range documentIDs {
+		doc := currentByID[id]
+		if err := s.consentRepo.RecordConsent(userID, id, ip, userAgent); err != nil {
+			return err
+		}
+
+		s.auditService.LogEvent(models.AuditEventConsentAccepted, &userID, map[string]interface{}{
+			"document_id": doc.ID,
+			"type":        doc.Type,
+			"version":     doc.Version,
+		}, ip, userAgent)
+	}
+
+	return nil
+}
+
+func (s *ConsentService) PendingForUser(userID uuid.UUID) ([]models.ConsentDocument, error) {
+	return s.consentRepo.ListPendingForUser(userID)
+}
+
+func (s *ConsentService) UserConsents(userID uuid.UUID) ([]models.UserConsent, error) {
+	return s.consentRepo.ListUserConsents(userID)
+}
+
+func (s *ConsentService) Report(documentID, page, perPage int) (*models.PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	entries, total, err := s.consentRepo.Report(documentID, page, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / perPage
+	if int(total)%perPage > 0 {
+		totalPages++
+	}
+
+	return &models.PaginatedResponse{
+		Data:       entries,
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
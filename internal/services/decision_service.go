@@ -0,0 +1,197 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/auth-service/internal/config"
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/auth-service/internal/utils"
+	"github.com/google/uuid"
+)
+
+const maxAuthzBatchSize = 100
+
+var (
+	ErrInvalidAuthzCheck  = errors.New("each check needs an action and a subject with either user_id or token")
+	ErrAuthzBatchTooLarge = errors.New("too many checks in one batch")
+)
+
+// Reasons reported with a decision.
+const (
+	AuthzReasonPolicy            = "policy"
+	AuthzReasonPermission        = "permission_granted"
+	AuthzReasonMissingPermission = "missing_permission"
+	AuthzReasonInvalidToken      = "invalid_token"
+	AuthzReasonSessionRevoked    = "session_revoked"
+	AuthzReasonTokenStale        = "token_stale"
+	AuthzReasonUserNotFound      = "user_not_found"
+	AuthzReasonUserInactive      = "user_inactive"
+	AuthzReasonNotMember         = "not_organization_member"
+)
+
+// DecisionService answers authorization questions for other services, so
+// they do not have to decode our tokens and reimplement role checks. It
+// resolves access exactly like the RequirePolicy middleware does.
+type DecisionService struct {
+	jwtManager   *utils.JWTManager
+	userRepo     *repository.UserRepository
+	orgRepo      *repository.OrganizationRepository
+	denylist     *TokenDenylist
+	authzService *AuthzService
+	auditService *AuditService
+}
+
+func NewDecisionService(
+	cfg *config.Config,
+	userRepo *repository.UserRepository,
+	orgRepo *repository.OrganizationRepository,
+	denylist *TokenDenylist,
+	authzService *AuthzService,
+	auditService *AuditService,
+) *DecisionService {
+	return &DecisionService{
+		jwtManager:   utils.NewJWTManager(cfg.JWTSigningKey, cfg.AccessTokenExpiry),
+		userRepo:     userRepo,
+		orgRepo:      orgRepo,
+		denylist:     denylist,
+		authzService: authzService,
+		auditService: auditService,
+	}
+}
+
+func (s *DecisionService) Check(req models.AuthzCheckRequest, checkedBy uuid.UUID, ip, userAgent string) (*models.AuthzDecision, error) {
+	if !validAuthzCheck(req) {
+		return nil, ErrInvalidAuthzCheck
+	}
+	return s.check(req, req.Audit, checkedBy, ip, userAgent)
+}
+
+// CheckBatch decides every check in order. The batch is rejected as a whole
+// if any check is malformed.
+func (s *DecisionService) CheckBatch(req models.AuthzBatchCheckRequest, checkedBy uuid.UUID, ip, userAgent string) ([]models.AuthzDecision, error) {
+	if len(req.Checks) == 0 {
+		return nil, ErrInvalidAuthzCheck
+	}
+	if len(req.Checks) > maxAuthzBatchSize {
+		return nil, ErrAuthzBatchTooLarge
+	}
+	for _, check := range req.Checks {
+		if !validAuthzCheck(check) {
+			return nil, ErrInvalidAuthzCheck
+		}
+	}
+
+	decisions := make([]models.AuthzDecision, 0, len(req.Checks))
+	for _, check := range req.Checks {
+		decision, err := s.check(check, req.Audit || check.Audit, checkedBy, ip, userAgent)
+		if err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, *decision)
+	}
+	return decisions, nil
+}
+
+func validAuthzCheck(req models.AuthzCheckRequest) bool {
+	hasUser := req.Subject.UserID != nil
+	hasToken := req.Subject.Token != ""
+	return req.Action != "" && hasUser != hasToken
+}
+
+func (s *DecisionService) check(req models.AuthzCheckRequest, audit bool, checkedBy uuid.UUID, ip, userAgent string) (*models.AuthzDecision, error) {
+	decision, err := s.decide(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if audit {
+		s.auditService.LogEvent(models.AuditEventAuthzDecision, decision.UserID, map[string]interface{}{
+			"action":        req.Action,
+			"resource_type": req.Resource.Type,
+			"resource_id":   req.Resource.ID,
+			"allowed":       decision.Allowed,
+			"reason":        decision.Reason,
+			"policy":        decision.Policy,
+			"checked_by":    checkedBy.String(),
+		}, ip, userAgent)
+	}
+
+	return decision, nil
+}
+
+func (s *DecisionService) decide(req models.AuthzCheckRequest) (*models.AuthzDecision, error) {
+	var userID, orgID uuid.UUID
+	var tokenStamp string
+	if req.Subject.Token != "" {
+		claims, err := s.jwtManager.ValidateToken(req.Subject.Token)
+		if err != nil {
+			return &models.AuthzDecision{Reason: AuthzReasonInvalidToken}, nil
+		}
+		if s.denylist.IsRevoked(claims) {
+			return &models.AuthzDecision{Reason: AuthzReasonSessionRevoked, UserID: &claims.UserID}, nil
+		}
+		userID, orgID, tokenStamp = claims.UserID, claims.OrganizationID, claims.SecurityStamp
+	} else {
+		userID = *req.Subject.UserID
+		if req.Subject.OrganizationID != nil {
+			orgID = *req.Subject.OrganizationID
+		}
+	}
+	if orgID == uuid.Nil {
+		orgID = models.DefaultOrganizationID
+	}
+
+	decision := &models.AuthzDecision{UserID: &userID}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		decision.Reason = AuthzReasonUserNotFound
+		return decision, nil
+	}
+	if !user.IsActive || user.IsSuspended(time.Now()) {
+		decision.Reason = AuthzReasonUserInactive
+		return decision, nil
+	}
+	if req.Subject.Token != "" && tokenStamp != user.SecurityStamp {
+		decision.Reason = AuthzReasonTokenStale
+		return decision, nil
+	}
+
+	member, err := s.orgRepo.IsMember(orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		decision.Reason = AuthzReasonNotMember
+		return decision, nil
+	}
+
+	access, err := s.authzService.Authorize(AccessRequest{
+		UserID:         userID,
+		OrganizationID: orgID,
+		Action:         req.Action,
+		Resource: PolicyResource{
+			Type:       req.Resource.Type,
+			ID:         req.Resource.ID,
+			Attributes: req.Resource.Attributes,
+		},
+		IP: req.IP,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	decision.Allowed = access.Allowed
+	decision.Policy = access.Policy
+	switch {
+	case access.Policy != "":
+		decision.Reason = AuthzReasonPolicy
+	case access.Allowed:
+		decision.Reason = AuthzReasonPermission
+	default:
+		decision.Reason = AuthzReasonMissingPermission
+	}
+	return decision, nil
+}
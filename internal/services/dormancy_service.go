@@ -0,0 +1,160 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/auth-service/internal/config"
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/auth-service/internal/utils"
+	"github.com/google/uuid"
+)
+
+type DormancyService struct {
+	cfg            *config.Config
+	userRepo       *repository.UserRepository
+	orgRepo        *repository.OrganizationRepository
+	roleRepo       *repository.RoleRepository
+	tokenRepo      *repository.TokenRepository
+	securityStamps *SecurityStamps
+	emailService   *EmailService
+	auditService   *AuditService
+	exempt         map[string]bool
+}
+
+func NewDormancyService(
+	cfg *config.Config,
+	userRepo *repository.UserRepository,
+	orgRepo *repository.OrganizationRepository,
+	roleRepo *repository.RoleRepository,
+	tokenRepo *repository.TokenRepository,
+	securityStamps *SecurityStamps,
+	emailService *EmailService,
+	auditService *AuditService,
+) *DormancyService {
+	exempt := make(map[string]bool, len(cfg.DormancyExemptEmails))
+	for _, email := range cfg.DormancyExemptEmails {
+		exempt[utils.SanitizeEmail(email)] = true
+	}
+
+	return &DormancyService{
+		cfg:            cfg,
+		userRepo:       userRepo,
+		orgRepo:        orgRepo,
+		roleRepo:       roleRepo,
+		tokenRepo:      tokenRepo,
+		securityStamps: securityStamps,
+		emailService:   emailService,
+		auditService:   auditService,
+		exempt:         exempt,
+	}
+}
+
+func (s *DormancyService) ProcessDormantAccounts() error {
+	now := time.Now()
+
+	// One step failing doesn't hold up the other.
+	return errors.Join(s.deactivateWarned(now), s.warnDormant(now))
+}
+
+func (s *DormancyService) warnDormant(now time.Time) error {
+	users, err := s.userRepo.ListDormantUnwarned(now.Add(-s.cfg.DormancyThreshold))
+	if err != nil {
+		return err
+	}
+
+	// A user that fails is reported but doesn't stop the others from being
+	// warned; it is retried on the next run.
+	var errs []error
+	deactivateAt := now.Add(s.cfg.DormancyGracePeriod)
+	for _, user := range users {
+		if s.isExempt(user) {
+			continue
+		}
+
+		if err := s.userRepo.MarkDormancyWarned(user.ID, now); err != nil {
+			errs = append(errs, fmt.Errorf("warn user %s: %w", user.ID, err))
+			continue
+		}
+
+		go s.emailService.SendDormancyWarningEmail(user.Email, user.DisplayName, deactivateAt)
+
+		s.auditService.LogEvent(models.AuditEventDormancyWarning, &user.ID, map[string]interface{}{
+			"last_activity": lastActivity(user).UTC().Format(time.RFC3339),
+			"deactivate_at": deactivateAt.UTC().Format(time.RFC3339),
+		}, "", "system")
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *DormancyService) deactivateWarned(now time.Time) error {
+	users, err := s.userRepo.ListDormancyWarnedBefore(now.Add(-s.cfg.DormancyGracePeriod))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, user := range users {
+		if s.isExempt(user) {
+			continue
+		}
+
+		// Logging in clears the warning, but check again in case the
+		// account was used after the warning went out.
+		if user.DormancyWarnedAt != nil && lastActivity(user).After(*user.DormancyWarnedAt) {
+			continue
+		}
+
+		if err := s.deactivate(user); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *DormancyService) deactivate(user models.User) error {
+	// A dormant last admin stays active; the refusal is audited so someone
+	// can appoint another admin.
+	err := requireOtherAdminEverywhere(s.orgRepo, s.roleRepo, s.auditService, user.ID, uuid.Nil, "dormancy_deactivate", "", "system")
+	if err == ErrLastAdmin {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("check admins for user %s: %w", user.ID, err)
+	}
+
+	if err := s.userRepo.Deactivate(user.ID); err != nil {
+		return fmt.Errorf("deactivate user %s: %w", user.ID, err)
+	}
+
+	if err := s.tokenRepo.RevokeAllUserTokens(user.ID); err != nil {
+		return fmt.Errorf("revoke tokens for user %s: %w", user.ID, err)
+	}
+
+	if err := s.securityStamps.Rotate(user.ID); err != nil {
+		return fmt.Errorf("rotate security stamp for user %s: %w", user.ID, err)
+	}
+
+	s.auditService.LogEvent(models.AuditEventAccountDeactivated, &user.ID, map[string]interface{}{
+		"reason":        "dormant",
+		"last_activity": lastActivity(user).UTC().Format(time.RFC3339),
+	}, "", "system")
+
+	return nil
+}
+
+func (s *DormancyService) isExempt(user models.User) bool {
+	return s.exempt[strings.ToLower(user.Email)]
+}
+
+func lastActivity(user models.User) time.Time {
+	if user.LastLoginAt != nil {
+		return *user.LastLoginAt
+	}
+	return user.CreatedAt
+}
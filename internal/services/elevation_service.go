@@ -0,0 +1,279 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/auth-service/internal/config"
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidElevation     = errors.New("invalid elevation request")
+	ErrElevationNotFound    = errors.New("elevation request not found")
+	ErrElevationExists      = errors.New("a pending elevation request already exists for this role")
+	ErrElevationNotPending  = errors.New("elevation request is no longer pending")
+	ErrRoleAlreadyHeld      = errors.New("user already holds this role permanently")
+	ErrNotElevationApprover = errors.New("only elevation approvers can decide on elevation requests")
+	ErrSelfApproval         = errors.New("cannot decide on your own elevation request")
+)
+
+// ElevationService handles just-in-time access: instead of holding a
+// privileged role permanently, a user asks for it for a limited time and an
+// approver grants it. The grant is an ordinary time-bound role assignment,
+// so it lapses and is swept like any other.
+type ElevationService struct {
+	cfg            *config.Config
+	elevationRepo  *repository.ElevationRepository
+	roleRepo       *repository.RoleRepository
+	orgRepo        *repository.OrganizationRepository
+	securityStamps *SecurityStamps
+	auditService   *AuditService
+}
+
+func NewElevationService(
+	cfg *config.Config,
+	elevationRepo *repository.ElevationRepository,
+	roleRepo *repository.RoleRepository,
+	orgRepo *repository.OrganizationRepository,
+	securityStamps *SecurityStamps,
+	auditService *AuditService,
+) *ElevationService {
+	return &ElevationService{
+		cfg:            cfg,
+		elevationRepo:  elevationRepo,
+		roleRepo:       roleRepo,
+		orgRepo:        orgRepo,
+		securityStamps: securityStamps,
+		auditService:   auditService,
+	}
+}
+
+func (s *ElevationService) RequestElevation(req models.CreateElevationRequest, orgID, userID uuid.UUID, ip, userAgent string) (*models.ElevationRequest, error) {
+	justification := strings.TrimSpace(req.Justification)
+	if justification == "" {
+		return nil, fmt.Errorf("%w: justification is required", ErrInvalidElevation)
+	}
+
+	maxMinutes := int(s.cfg.ElevationMaxDuration / time.Minute)
+	if req.DurationMinutes <= 0 || req.DurationMinutes > maxMinutes {
+		return nil, fmt.Errorf("%w: duration_minutes must be between 1 and %d", ErrInvalidElevation, maxMinutes)
+	}
+
+	if _, err := s.roleRepo.GetByID(req.RoleID); err != nil {
+		return nil, ErrRoleNotFound
+	}
+
+	if err := s.requireNotHeld(orgID, userID, req.RoleID); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.elevationRepo.HasPending(orgID, userID, req.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, ErrElevationExists
+	}
+
+	now := time.Now()
+	e := &models.ElevationRequest{
+		ID:              uuid.New(),
+		OrganizationID:  orgID,
+		UserID:          userID,
+		RoleID:          req.RoleID,
+		DurationMinutes: req.DurationMinutes,
+		Justification:   justification,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(s.cfg.ElevationRequestExpiry),
+		Status:          models.ElevationStatusPending,
+	}
+
+	if err := s.elevationRepo.Create(e); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogEvent(models.AuditEventElevationRequested, &userID, map[string]interface{}{
+		"elevation_id":     e.ID.String(),
+		"organization_id":  orgID,
+		"role_id":          e.RoleID,
+		"duration_minutes": e.DurationMinutes,
+		"justification":    e.Justification,
+	}, ip, userAgent)
+
+	return e, nil
+}
+
+// ListPending returns the organization's pending requests to approvers, and
+// their own pending requests to everyone else.
+func (s *ElevationService) ListPending(orgID, viewerID uuid.UUID) ([]models.ElevationRequest, error) {
+	scope, err := s.viewScope(orgID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.elevationRepo.ListPending(orgID, scope)
+}
+
+// ListActive returns the elevations currently in force, scoped like
+// ListPending.
+func (s *ElevationService) ListActive(orgID, viewerID uuid.UUID) ([]models.ElevationRequest, error) {
+	scope, err := s.viewScope(orgID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.elevationRepo.ListActive(orgID, scope)
+}
+
+func (s *ElevationService) viewScope(orgID, viewerID uuid.UUID) (*uuid.UUID, error) {
+	approver, err := s.isApprover(orgID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if approver {
+		return nil, nil
+	}
+	return &viewerID, nil
+}
+
+func (s *ElevationService) Approve(id, orgID, approverID uuid.UUID, note, ip, userAgent string) (*models.ElevationRequest, error) {
+	e, err := s.getForDecision(id, orgID, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The requester may have left the organization or been given the role
+	// for good since asking.
+	if err := requireMember(s.orgRepo, orgID, e.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.requireNotHeld(orgID, e.UserID, e.RoleID); err != nil {
+		return nil, err
+	}
+
+	// Approving grants the role, so it takes the same scope as assigning it.
+	role, err := s.roleRepo.GetByID(e.RoleID)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+	if err := requireManageable(s.roleRepo, orgID, approverID, role); err != nil {
+		return nil, err
+	}
+
+	grantExpiresAt := time.Now().Add(time.Duration(e.DurationMinutes) * time.Minute)
+	approved, err := s.elevationRepo.Approve(e, approverID, note, grantExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if !approved {
+		return nil, ErrElevationNotPending
+	}
+
+	if err := s.securityStamps.Rotate(e.UserID); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogEvent(models.AuditEventElevationApproved, &e.UserID, map[string]interface{}{
+		"elevation_id":    e.ID.String(),
+		"organization_id": orgID,
+		"role_id":         e.RoleID,
+		"approved_by":     approverID.String(),
+		"expires_at":      grantExpiresAt.UTC().Format(time.RFC3339),
+		"note":            note,
+	}, ip, userAgent)
+
+	return s.elevationRepo.GetByID(e.ID)
+}
+
+func (s *ElevationService) Deny(id, orgID, approverID uuid.UUID, note, ip, userAgent string) (*models.ElevationRequest, error) {
+	e, err := s.getForDecision(id, orgID, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	denied, err := s.elevationRepo.Deny(e.ID, approverID, note)
+	if err != nil {
+		return nil, err
+	}
+	if !denied {
+		return nil, ErrElevationNotPending
+	}
+
+	s.auditService.LogEvent(models.AuditEventElevationDenied, &e.UserID, map[string]interface{}{
+		"elevation_id":    e.ID.String(),
+		"organization_id": orgID,
+		"role_id":         e.RoleID,
+		"denied_by":       approverID.String(),
+		"note":            note,
+	}, ip, userAgent)
+
+	return s.elevationRepo.GetByID(e.ID)
+}
+
+// Cancel lets the requester withdraw a request nobody has decided on yet.
+func (s *ElevationService) Cancel(id, orgID, userID uuid.UUID, ip, userAgent string) error {
+	e, err := s.elevationRepo.GetByID(id)
+	if err != nil || e.OrganizationID != orgID || e.UserID != userID {
+		return ErrElevationNotFound
+	}
+
+	cancelled, err := s.elevationRepo.Cancel(e.ID)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return ErrElevationNotPending
+	}
+
+	s.auditService.LogEvent(models.AuditEventElevationCancelled, &userID, map[string]interface{}{
+		"elevation_id":    e.ID.String(),
+		"organization_id": orgID,
+		"role_id":         e.RoleID,
+	}, ip, userAgent)
+
+	return nil
+}
+
+func (s *ElevationService) getForDecision(id, orgID, approverID uuid.UUID) (*models.ElevationRequest, error) {
+	approver, err := s.isApprover(orgID, approverID)
+	if err != nil {
+		return nil, err
+	}
+	if !approver {
+		return nil, ErrNotElevationApprover
+	}
+
+	e, err := s.elevationRepo.GetByID(id)
+	if err != nil || e.OrganizationID != orgID {
+		return nil, ErrElevationNotFound
+	}
+	if e.UserID == approverID {
+		return nil, ErrSelfApproval
+	}
+	if e.Status != models.ElevationStatusPending {
+		return nil, ErrElevationNotPending
+	}
+	return e, nil
+}
+
+func (s *ElevationService) isApprover(orgID, userID uuid.UUID) (bool, error) {
+	return s.roleRepo.UserHasRole(userID, orgID, s.cfg.ElevationApproverRole)
+}
+
+// requireNotHeld refuses elevation to a role the user was assigned without
+// an expiry, since there would be nothing to elevate to.
+func (s *ElevationService) requireNotHeld(orgID, userID uuid.UUID, roleID int) error {
+	grants, err := s.roleRepo.ListUserGrants(userID, orgID)
+	if err != nil {
+		return err
+	}
+	for _, grant := range grants {
+		if grant.RoleID == roleID && grant.ExpiresAt == nil {
+			return ErrRoleAlreadyHeld
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/auth-service/internal/config"
 	"gopkg.in/gomail.v2"
@@ -48,6 +49,78 @@ func (s *EmailService) SendPasswordResetEmail(to, displayName, token string) err
 	return s.sendEmail(to, subject, body)
 }
 
+func (s *EmailService) SendInvitationEmail(to, displayName, token string, expiresAt time.Time) error {
+	if displayName == "" {
+		displayName = to
+	}
+
+	subject := "You Have Been Invited"
+	body := fmt.Sprintf(`
+		<h2>Hello %s,</h2>
+		<p>An administrator has created an account for you. Set your password to activate it using the following token:</p>
+		<p><strong>Token: %s</strong></p>
+		<p>This invitation will expire on %s.</p>
+		<p>If you were not expecting this invitation, please ignore this email.</p>
+	`, displayName, token, expiresAt.UTC().Format("2006-01-02 15:04 MST"))
+
+	return s.sendEmail(to, subject, body)
+}
+
+func (s *EmailService) SendOrganizationInvitationEmail(to, displayName, organization, token string, expiresAt time.Time) error {
+	subject := "You Have Been Invited to " + organization
+	body := fmt.Sprintf(`
+		<h2>Hello %s,</h2>
+		<p>An administrator of %s has invited you to join their organization. To accept, sign in and submit the following token:</p>
+		<p><strong>Token: %s</strong></p>
+		<p>This invitation will expire on %s.</p>
+		<p>If you do not want to join, ignore this email and nothing will change.</p>
+	`, displayName, organization, token, expiresAt.UTC().Format("2006-01-02 15:04 MST"))
+
+	return s.sendEmail(to, subject, body)
+}
+
+func (s *EmailService) SendDormancyWarningEmail(to, displayName string, deactivateAt time.Time) error {
+	subject := "Your Account Will Be Deactivated"
+	body := fmt.Sprintf(`
+		<h2>Hello %s,</h2>
+		<p>We have not seen you sign in for a long time.</p>
+		<p>Your account will be deactivated on %s unless you sign in before then.</p>
+		<p>If you no longer need this account, no action is required.</p>
+	`, displayName, deactivateAt.UTC().Format("2006-01-02 15:04 MST"))
+
+	return s.sendEmail(to, subject, body)
+}
+
+func (s *EmailService) SendNewDeviceEmail(to, displayName, device, ip string, at time.Time, token string) error {
+	subject := "New Sign-In to Your Account"
+	body := fmt.Sprintf(`
+		<h2>Hello %s,</h2>
+		<p>Your account was just accessed from a device we have not seen before.</p>
+		<p><strong>Device:</strong> %s<br><strong>IP address:</strong> %s<br><strong>Time:</strong> %s</p>
+		<p>If this was you, no action is required.</p>
+		<p>If this wasn't you, sign that device out and reset your password using the following token:</p>
+		<p><strong>Token: %s</strong></p>
+		<p>Or click the link below and confirm:</p>
+		<a href="http://localhost:8080/api/v1/auth/not-me?token=%s">This wasn't me</a>
+	`, displayName, device, ip, at.UTC().Format("2006-01-02 15:04 MST"), token, token)
+
+	return s.sendEmail(to, subject, body)
+}
+
+func (s *EmailService) SendStepUpCodeEmail(to, displayName, code, location string) error {
+	subject := "Confirm Your Sign-In"
+	body := fmt.Sprintf(`
+		<h2>Hello %s,</h2>
+		<p>We received a sign-in to your account from %s, which is unusually far from where you last signed in.</p>
+		<p>To continue, enter the following code:</p>
+		<p><strong>Code: %s</strong></p>
+		<p>This code will expire in 10 minutes.</p>
+		<p>If this wasn't you, change your password immediately.</p>
+	`, displayName, location, code)
+
+	return s.sendEmail(to, subject, body)
+}
+
 func (s *EmailService) sendEmail(to, subject, body string) error {
 	if s.cfg.SMTPUser == "" {
 		fmt.Printf("[EMAIL] To: %s, Subject: %s\n", to, subject)
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"math"
+	"net"
+	"time"
+
+	"github.com/auth-service/internal/config"
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+const earthRadiusKm = 6371.0
+
+// GeoIP city databases are only accurate to a metro area, so short hops
+// are never treated as travel.
+const minTravelDistanceKm = 300.0
+
+type cityRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Location struct {
+		Latitude  float64 `maxminddb:"latitude"`
+		Longitude float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+type asnRecord struct {
+	Number       uint   `maxminddb:"autonomous_system_number"`
+	Organization string `maxminddb:"autonomous_system_organization"`
+}
+
+type TravelCheck struct {
+	From        *models.GeoLocation
+	DistanceKm  float64
+	SpeedKmh    float64
+	Implausible bool
+}
+
+type GeoService struct {
+	cfg       *config.Config
+	auditRepo *repository.AuditRepository
+	city      *maxminddb.Reader
+	asn       *maxminddb.Reader
+}
+
+func NewGeoService(cfg *config.Config, auditRepo *repository.AuditRepository) (*GeoService, error) {
+	s := &GeoService{
+		cfg:       cfg,
+		auditRepo: auditRepo,
+	}
+
+	if cfg.GeoIPCityDB != "" {
+		reader, err := maxminddb.Open(cfg.GeoIPCityDB)
+		if err != nil {
+			return nil, err
+		}
+		s.city = reader
+	}
+
+	if cfg.GeoIPASNDB != "" {
+		reader, err := maxminddb.Open(cfg.GeoIPASNDB)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.asn = reader
+	}
+
+	return s, nil
+}
+
+func (s *GeoService) Close() {
+	if s.city != nil {
+		s.city.Close()
+	}
+	if s.asn != nil {
+		s.asn.Close()
+	}
+}
+
+// Lookup returns what the configured databases know about the address, or
+// nil when GeoIP is disabled or the address is not listed (e.g. private).
+func (s *GeoService) Lookup(ip string) *models.GeoLocation {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || (s.city == nil && s.asn == nil) {
+		return nil
+	}
+
+	geo := &models.GeoLocation{}
+
+	if s.city != nil {
+		var record cityRecord
+		if err := s.city.Lookup(parsed, &record); err == nil {
+			geo.Country = record.Country.ISOCode
+			geo.City = record.City.Names["en"]
+			geo.Latitude = record.Location.Latitude
+			geo.Longitude = record.Location.Longitude
+		}
+	}
+
+	if s.asn != nil {
+		var record asnRecord
+		if err := s.asn.Lookup(parsed, &record); err == nil {
+			geo.ASN = record.Number
+			geo.ASOrg = record.Organization
+		}
+	}
+
+	if geo.Country == "" && geo.ASN == 0 && !geo.HasCoordinates() {
+		return nil
+	}
+	return geo
+}
+
+// CheckTravel compares a login location with the user's previous located
+// login and reports whether getting from one to the other in the elapsed
+// time would have required moving faster than the configured speed.
+func (s *GeoService) CheckTravel(userID uuid.UUID, current *models.GeoLocation, at time.Time) *TravelCheck {
+	if !current.HasCoordinates() || s.cfg.ImpossibleTravelSpeed <= 0 {
+		return nil
+	}
+
+	previous, err := s.auditRepo.LastLoginLocation(userID)
+	if err != nil || !previous.Geo.HasCoordinates() {
+		return nil
+	}
+
+	distance := haversineKm(previous.Geo, current)
+	hours := at.Sub(previous.CreatedAt).Hours()
+	if hours < 1.0/60 {
+		hours = 1.0 / 60
+	}
+
+	check := &TravelCheck{
+		From:       previous.Geo,
+		DistanceKm: distance,
+		SpeedKmh:   distance / hours,
+	}
+	check.Implausible = distance >= minTravelDistanceKm && check.SpeedKmh > float64(s.cfg.ImpossibleTravelSpeed)
+
+	return check
+}
+
+func haversineKm(a, b *models.GeoLocation) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
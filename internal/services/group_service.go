@@ -0,0 +1,408 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrGroupNotFound = errors.New("group not found")
+	ErrGroupExists   = errors.New("a group with this name already exists")
+)
+
+type GroupService struct {
+	groupRepo      *repository.GroupRepository
+	orgRepo        *repository.OrganizationRepository
+	roleRepo       *repository.RoleRepository
+	securityStamps *SecurityStamps
+	auditService   *AuditService
+}
+
+func NewGroupService(
+	groupRepo *repository.GroupRepository,
+	orgRepo *repository.OrganizationRepository,
+	roleRepo *repository.RoleRepository,
+	securityStamps *SecurityStamps,
+	auditService *AuditService,
+) *GroupService {
+	return &GroupService{
+		groupRepo:      groupRepo,
+		orgRepo:        orgRepo,
+		roleRepo:       roleRepo,
+		securityStamps: securityStamps,
+		auditService:   auditService,
+	}
+}
+
+// getGroup loads a group of the caller's organization; groups of other
+// organizations are reported as not found.
+func (s *GroupService) getGroup(orgID, groupID uuid.UUID) (*models.Group, error) {
+	group, err := s.groupRepo.GetByID(groupID)
+	if err != nil || group.OrganizationID != orgID {
+		return nil, ErrGroupNotFound
+	}
+	return group, nil
+}
+
+func (s *GroupService) ListGroups(orgID uuid.UUID) ([]models.Group, error) {
+	return s.groupRepo.ListByOrganization(orgID)
+}
+
+func (s *GroupService) GetGroup(orgID, groupID uuid.UUID) (*models.Group, error) {
+	return s.getGroup(orgID, groupID)
+}
+
+func (s *GroupService) CreateGroup(orgID uuid.UUID, req models.CreateGroupRequest, createdBy uuid.UUID, ip, userAgent string) (*models.Group, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	if existing, _ := s.groupRepo.GetByName(orgID, name); existing != nil {
+		return nil, ErrGroupExists
+	}
+
+	if err := s.requireManageableRoles(orgID, createdBy, req.RoleIDs); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	group := &models.Group{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		Name:           name,
+		Description:    req.Description,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.groupRepo.Create(group); err != nil {
+		return nil, err
+	}
+
+	for _, roleID := range req.RoleIDs {
+		if err := s.groupRepo.AssignRole(group.ID, roleID, createdBy); err != nil {
+			return nil, err
+		}
+	}
+
+	s.auditService.LogEvent(models.AuditEventGroupChange, &createdBy, map[string]interface{}{
+		"action":          "create",
+		"group_id":        group.ID,
+		"organization_id": orgID,
+		"name":            group.Name,
+		"role_ids":        req.RoleIDs,
+	}, ip, userAgent)
+
+	return s.groupRepo.GetByID(group.ID)
+}
+
+func (s *GroupService) UpdateGroup(orgID, groupID uuid.UUID, req models.UpdateGroupRequest, updatedBy uuid.UUID, ip, userAgent string) (*models.Group, error) {
+	group, err := s.getGroup(orgID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("name is required")
+		}
+		if existing, _ := s.groupRepo.GetByName(orgID, name); existing != nil && existing.ID != groupID {
+			return nil, ErrGroupExists
+		}
+		group.Name = name
+	}
+
+	if req.Description != nil {
+		group.Description = *req.Description
+	}
+
+	group.UpdatedAt = time.Now()
+	if err := s.groupRepo.Update(group); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogEvent(models.AuditEventGroupChange, &updatedBy, map[string]interface{}{
+		"action":   "update",
+		"group_id": groupID,
+		"name":     group.Name,
+	}, ip, userAgent)
+
+	return group, nil
+}
+
+// DeleteGroup removes the group; its members lose the roles it granted, and
+// each of them gets a membership audit event.
+func (s *GroupService) DeleteGroup(orgID, groupID, deletedBy uuid.UUID, ip, userAgent string) error {
+	group, err := s.getGroup(orgID, groupID)
+	if err != nil {
+		return err
+	}
+
+	members, err := s.groupRepo.ListMembers(groupID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.requireAdminOutsideGroup(orgID, group.RoleIDs, members, deletedBy, "delete_group", ip, userAgent); err != nil {
+		return err
+	}
+
+	if err := s.groupRepo.Delete(groupID); err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		userID := member.UserID
+		if len(group.RoleIDs) > 0 {
+			if err := s.securityStamps.Rotate(userID); err != nil {
+				return err
+			}
+		}
+		s.auditService.LogEvent(models.AuditEventGroupMemberRemoved, &userID, map[string]interface{}{
+			"group_id":   groupID,
+			"removed_by": deletedBy.String(),
+			"reason":     "group_deleted",
+		}, ip, userAgent)
+	}
+
+	s.auditService.LogEvent(models.AuditEventGroupChange, &deletedBy, map[string]interface{}{
+		"action":   "delete",
+		"group_id": groupID,
+		"name":     group.Name,
+		"members":  len(members),
+	}, ip, userAgent)
+
+	return nil
+}
+
+func (s *GroupService) ListMembers(orgID, groupID uuid.UUID) ([]models.GroupMember, error) {
+	if _, err := s.getGroup(orgID, groupID); err != nil {
+		return nil, err
+	}
+	return s.groupRepo.ListMembers(groupID)
+}
+
+// AddMembers adds users in bulk. Every user must belong to the group's
+// organization; nothing is added if any of them does not. It returns the
+// number of users that were not already members.
+func (s *GroupService) AddMembers(orgID, groupID uuid.UUID, userIDs []uuid.UUID, addedBy uuid.UUID, ip, userAgent string) (int, error) {
+	group, err := s.getGroup(orgID, groupID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.requireManageableRoles(orgID, addedBy, group.RoleIDs); err != nil {
+		return 0, err
+	}
+
+	for _, userID := range userIDs {
+		if err := requireMember(s.orgRepo, orgID, userID); err != nil {
+			return 0, err
+		}
+	}
+
+	added := 0
+	for _, userID := range userIDs {
+		ok, err := s.groupRepo.AddMember(groupID, userID, addedBy)
+		if err != nil {
+			return added, err
+		}
+		if !ok {
+			continue
+		}
+		added++
+
+		if len(group.RoleIDs) > 0 {
+			if err := s.securityStamps.Rotate(userID); err != nil {
+				return added, err
+			}
+		}
+
+		s.auditService.LogEvent(models.AuditEventGroupMemberAdded, &userID, map[string]interface{}{
+			"group_id": groupID,
+			"added_by": addedBy.String(),
+		}, ip, userAgent)
+	}
+
+	return added, nil
+}
+
+func (s *GroupService) RemoveMember(orgID, groupID, userID, removedBy uuid.UUID, ip, userAgent string) error {
+	group, err := s.getGroup(orgID, groupID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.requireManageableRoles(orgID, removedBy, group.RoleIDs); err != nil {
+		return err
+	}
+
+	grantsAdmin, err := anyRoleGrants(s.roleRepo, group.RoleIDs, adminRoleName)
+	if err != nil {
+		return err
+	}
+	if grantsAdmin {
+		if err := requireOtherAdmin(s.roleRepo, s.auditService, orgID, userID, removedBy, "remove_group_member", ip, userAgent); err != nil {
+			return err
+		}
+	}
+
+	removed, err := s.groupRepo.RemoveMember(groupID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrUserNotFound
+	}
+
+	if len(group.RoleIDs) > 0 {
+		if err := s.securityStamps.Rotate(userID); err != nil {
+			return err
+		}
+	}
+
+	s.auditService.LogEvent(models.AuditEventGroupMemberRemoved, &userID, map[string]interface{}{
+		"group_id":   groupID,
+		"removed_by": removedBy.String(),
+	}, ip, userAgent)
+
+	return nil
+}
+
+func (s *GroupService) AssignRole(orgID, groupID uuid.UUID, roleID int, assignedBy uuid.UUID, ip, userAgent string) error {
+	if _, err := s.getGroup(orgID, groupID); err != nil {
+		return err
+	}
+
+	role, err := s.roleRepo.GetByID(roleID)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+	if err := requireManageable(s.roleRepo, orgID, assignedBy, role); err != nil {
+		return err
+	}
+
+	if err := s.groupRepo.AssignRole(groupID, roleID, assignedBy); err != nil {
+		return err
+	}
+
+	if err := s.rotateMembers(groupID); err != nil {
+		return err
+	}
+
+	s.auditService.LogEvent(models.AuditEventGroupChange, &assignedBy, map[string]interface{}{
+		"action":   "assign_role",
+		"group_id": groupID,
+		"role_id":  roleID,
+	}, ip, userAgent)
+
+	return nil
+}
+
+func (s *GroupService) UnassignRole(orgID, groupID uuid.UUID, roleID int, removedBy uuid.UUID, ip, userAgent string) error {
+	group, err := s.getGroup(orgID, groupID)
+	if err != nil {
+		return err
+	}
+
+	role, err := s.roleRepo.GetByID(roleID)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+	if err := requireManageable(s.roleRepo, orgID, removedBy, role); err != nil {
+		return err
+	}
+
+	// Members keep admin if another role of the group still grants it.
+	var remaining []int
+	for _, id := range group.RoleIDs {
+		if id != roleID {
+			remaining = append(remaining, id)
+		}
+	}
+	keepsAdmin, err := anyRoleGrants(s.roleRepo, remaining, adminRoleName)
+	if err != nil {
+		return err
+	}
+	if !keepsAdmin {
+		members, err := s.groupRepo.ListMembers(groupID)
+		if err != nil {
+			return err
+		}
+		if err := s.requireAdminOutsideGroup(orgID, []int{roleID}, members, removedBy, "unassign_group_role", ip, userAgent); err != nil {
+			return err
+		}
+	}
+
+	removed, err := s.groupRepo.UnassignRole(groupID, roleID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrRoleNotFound
+	}
+
+	if err := s.rotateMembers(groupID); err != nil {
+		return err
+	}
+
+	s.auditService.LogEvent(models.AuditEventGroupChange, &removedBy, map[string]interface{}{
+		"action":   "unassign_role",
+		"group_id": groupID,
+		"role_id":  roleID,
+	}, ip, userAgent)
+
+	return nil
+}
+
+// requireManageableRoles checks that the actor may manage every role the
+// group grants, since adding or removing a member grants or takes them.
+func (s *GroupService) requireManageableRoles(orgID, actorID uuid.UUID, roleIDs []int) error {
+	for _, roleID := range roleIDs {
+		role, err := s.roleRepo.GetByID(roleID)
+		if err != nil {
+			return ErrRoleNotFound
+		}
+		if err := requireManageable(s.roleRepo, orgID, actorID, role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requireAdminOutsideGroup refuses to take roleIDs away from the group's
+// members when they grant admin and nobody outside the group would be left
+// to administer the organization.
+func (s *GroupService) requireAdminOutsideGroup(orgID uuid.UUID, roleIDs []int, members []models.GroupMember, actorID uuid.UUID, operation, ip, userAgent string) error {
+	grantsAdmin, err := anyRoleGrants(s.roleRepo, roleIDs, adminRoleName)
+	if err != nil || !grantsAdmin {
+		return err
+	}
+
+	userIDs := make([]uuid.UUID, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserID
+	}
+	return requireAdminOutside(s.roleRepo, s.auditService, orgID, userIDs, actorID, operation, ip, userAgent)
+}
+
+// rotateMembers makes the access tokens of every member stale after the
+// roles the group grants have changed.
+func (s *GroupService) rotateMembers(groupID uuid.UUID) error {
+	members, err := s.groupRepo.ListMembers(groupID)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		if err := s.securityStamps.Rotate(member.UserID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
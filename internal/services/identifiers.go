@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/auth-service/internal/utils"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidUsername   = errors.New("username must be 3-32 characters of lowercase letters, digits, '.', '_' or '-'")
+	ErrInvalidPhone      = errors.New("phone must be in E.164 format, e.g. +14155550123")
+	ErrDuplicateUsername = errors.New("username already exists")
+	ErrDuplicatePhone    = errors.New("phone already exists")
+)
+
+// normalizeUsername validates an optional username. An empty value clears it.
+func normalizeUsername(userRepo *repository.UserRepository, username string, userID uuid.UUID) (*string, error) {
+	username = utils.SanitizeUsername(username)
+	if username == "" {
+		return nil, nil
+	}
+
+	if !utils.ValidateUsername(username) {
+		return nil, ErrInvalidUsername
+	}
+
+	existing, _ := userRepo.GetByUsername(username)
+	if existing != nil && existing.ID != userID {
+		return nil, ErrDuplicateUsername
+	}
+
+	return &username, nil
+}
+
+// normalizePhone validates an optional phone number. An empty value clears it.
+func normalizePhone(userRepo *repository.UserRepository, phone string, userID uuid.UUID) (*string, error) {
+	phone = utils.SanitizePhone(phone)
+	if phone == "" {
+		return nil, nil
+	}
+
+	if !utils.ValidatePhone(phone) {
+		return nil, ErrInvalidPhone
+	}
+
+	existing, _ := userRepo.GetByPhone(phone)
+	if existing != nil && existing.ID != userID {
+		return nil, ErrDuplicatePhone
+	}
+
+	return &phone, nil
+}
+
+// lookupByIdentifier resolves a login identifier to a user. Anything with an
+// "@" is an email, a leading "+" is a phone number, the rest are usernames.
+func lookupByIdentifier(userRepo *repository.UserRepository, identifier string) (*models.User, string, error) {
+	identifier = strings.TrimSpace(identifier)
+
+	switch {
+	case strings.Contains(identifier, "@"):
+		email := utils.SanitizeEmail(identifier)
+		user, err := userRepo.GetByEmail(email)
+		return user, email, err
+	case strings.HasPrefix(identifier, "+"):
+		phone := utils.SanitizePhone(identifier)
+		user, err := userRepo.GetByPhone(phone)
+		return user, phone, err
+	default:
+		username := utils.SanitizeUsername(identifier)
+		user, err := userRepo.GetByUsername(username)
+		return user, username, err
+	}
+}
@@ -0,0 +1,402 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/auth-service/internal/config"
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/auth-service/internal/utils"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvitationNotFound   = errors.New("invitation not found")
+	ErrInvitationExists     = errors.New("a pending invitation already exists for this email")
+	ErrInvitationNotPending = errors.New("invitation is no longer pending")
+)
+
+type InvitationService struct {
+	cfg            *config.Config
+	invitationRepo *repository.InvitationRepository
+	userRepo       *repository.UserRepository
+	roleRepo       *repository.RoleRepository
+	orgRepo        *repository.OrganizationRepository
+	emailService   *EmailService
+	auditService   *AuditService
+}
+
+func NewInvitationService(
+	cfg *config.Config,
+	invitationRepo *repository.InvitationRepository,
+	userRepo *repository.UserRepository,
+	roleRepo *repository.RoleRepository,
+	orgRepo *repository.OrganizationRepository,
+	emailService *EmailService,
+	auditService *AuditService,
+) *InvitationService {
+	return &InvitationService{
+		cfg:            cfg,
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		orgRepo:        orgRepo,
+		emailService:   emailService,
+		auditService:   auditService,
+	}
+}
+
+func (s *InvitationService) CreateInvitation(req models.CreateInvitationRequest, orgID, invitedBy uuid.UUID, ip, userAgent string) (*models.Invitation, error) {
+	email := utils.SanitizeEmail(req.Email)
+
+	if !utils.ValidateEmail(email) {
+		return nil, errors.New("invalid email format")
+	}
+
+	pending, _ := s.invitationRepo.GetPendingByEmailInOrganization(orgID, email)
+	if pending != nil {
+		return nil, ErrInvitationExists
+	}
+
+	for _, roleID := range req.RoleIDs {
+		role, err := s.roleRepo.GetByID(roleID)
+		if err != nil {
+			return nil, errors.New("role not found")
+		}
+		if err := requireManageable(s.roleRepo, orgID, invitedBy, role); err != nil {
+			return nil, err
+		}
+	}
+
+	// An existing user is asked to join instead, and the response looks the
+	// same either way so it doesn't tell whether the email has an account.
+	existingUser, _ := s.userRepo.GetByEmail(email)
+	if existingUser != nil {
+		return s.inviteUser(orgID, existingUser, req.DisplayName, req.RoleIDs, invitedBy, ip, userAgent)
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	roleIDs := req.RoleIDs
+	if roleIDs == nil {
+		roleIDs = []int{}
+	}
+
+	now := time.Now()
+	inv := &models.Invitation{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		Email:          email,
+		DisplayName:    req.DisplayName,
+		RoleIDs:        roleIDs,
+		TokenHash:      utils.HashToken(token),
+		InvitedBy:      &invitedBy,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(s.cfg.InvitationExpiry),
+		Status:         models.InvitationStatusPending,
+	}
+
+	if err := s.invitationRepo.Create(inv); err != nil {
+		return nil, err
+	}
+
+	go s.emailService.SendInvitationEmail(inv.Email, inv.DisplayName, token, inv.ExpiresAt)
+
+	s.auditService.LogEvent(models.AuditEventInvitationCreated, &invitedBy, map[string]interface{}{
+		"invitation_id":   inv.ID.String(),
+		"organization_id": orgID,
+		"email":           inv.Email,
+		"role_ids":        inv.RoleIDs,
+	}, ip, userAgent)
+
+	return inv, nil
+}
+
+// InviteMember invites an existing user to the organization. Nobody is added
+// to an organization without agreeing to it: the user joins only once they
+// accept the emailed token with JoinOrganization while signed in.
+func (s *InvitationService) InviteMember(orgID uuid.UUID, req models.AddOrganizationMemberRequest, invitedBy uuid.UUID, ip, userAgent string) (*models.Invitation, error) {
+	user, err := s.userRepo.GetByID(req.UserID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	pending, _ := s.invitationRepo.GetPendingByEmailInOrganization(orgID, user.Email)
+	if pending != nil {
+		return nil, ErrInvitationExists
+	}
+
+	for _, roleID := range req.RoleIDs {
+		role, err := s.roleRepo.GetByID(roleID)
+		if err != nil {
+			return nil, ErrRoleNotFound
+		}
+		if err := requireManageable(s.roleRepo, orgID, invitedBy, role); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.inviteUser(orgID, user, user.DisplayName, req.RoleIDs, invitedBy, ip, userAgent)
+}
+
+// inviteUser sends an existing user an invitation to join the organization.
+// The checks on pending invitations and roles are left to the caller.
+func (s *InvitationService) inviteUser(orgID uuid.UUID, user *models.User, displayName string, roleIDs []int, invitedBy uuid.UUID, ip, userAgent string) (*models.Invitation, error) {
+	member, err := s.orgRepo.IsMember(orgID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if member {
+		return nil, ErrAlreadyOrgMember
+	}
+
+	org, err := s.orgRepo.GetByID(orgID)
+	if err != nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	if roleIDs == nil {
+		roleIDs = []int{}
+	}
+
+	now := time.Now()
+	inv := &models.Invitation{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		Email:          user.Email,
+		DisplayName:    displayName,
+		RoleIDs:        roleIDs,
+		TokenHash:      utils.HashToken(token),
+		InvitedBy:      &invitedBy,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(s.cfg.InvitationExpiry),
+		Status:         models.InvitationStatusPending,
+	}
+
+	if err := s.invitationRepo.Create(inv); err != nil {
+		return nil, err
+	}
+
+	go s.emailService.SendOrganizationInvitationEmail(user.Email, user.DisplayName, org.Name, token, inv.ExpiresAt)
+
+	s.auditService.LogEvent(models.AuditEventInvitationCreated, &invitedBy, map[string]interface{}{
+		"invitation_id":   inv.ID.String(),
+		"organization_id": orgID,
+		"user_id":         user.ID.String(),
+		"email":           inv.Email,
+		"role_ids":        inv.RoleIDs,
+	}, ip, userAgent)
+
+	return inv, nil
+}
+
+// JoinOrganization accepts an invitation sent with InviteMember. The token
+// only works for the account it was sent to.
+func (s *InvitationService) JoinOrganization(token string, userID uuid.UUID, ip, userAgent string) (*models.Organization, error) {
+	inv, err := s.invitationRepo.GetByTokenHash(utils.HashToken(token))
+	if err != nil || inv.StatusAt(time.Now()) != models.InvitationStatusPending {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || user.Email != inv.Email {
+		return nil, ErrInvalidToken
+	}
+
+	added, err := s.orgRepo.AddMember(inv.OrganizationID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, ErrAlreadyOrgMember
+	}
+
+	assignedBy := user.ID
+	if inv.InvitedBy != nil {
+		assignedBy = *inv.InvitedBy
+	}
+	for _, roleID := range inv.RoleIDs {
+		if err := s.roleRepo.AssignRoleToUser(user.ID, inv.OrganizationID, roleID, assignedBy, nil, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.invitationRepo.MarkAccepted(inv.ID, user.ID); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogEvent(models.AuditEventInvitationAccepted, &user.ID, map[string]interface{}{
+		"invitation_id":   inv.ID.String(),
+		"organization_id": inv.OrganizationID,
+		"role_ids":        inv.RoleIDs,
+	}, ip, userAgent)
+	s.auditService.LogEvent(models.AuditEventOrganizationMemberAdded, &user.ID, map[string]interface{}{
+		"organization_id": inv.OrganizationID,
+		"role_ids":        inv.RoleIDs,
+		"added_by":        assignedBy.String(),
+	}, ip, userAgent)
+
+	return s.orgRepo.GetByID(inv.OrganizationID)
+}
+
+func (s *InvitationService) ListPendingInvitations(orgID uuid.UUID) ([]models.Invitation, error) {
+	return s.invitationRepo.ListPending(orgID)
+}
+
+func (s *InvitationService) ResendInvitation(id, orgID, resentBy uuid.UUID, ip, userAgent string) (*models.Invitation, error) {
+	inv, err := s.invitationRepo.GetByID(id)
+	if err != nil || inv.OrganizationID != orgID {
+		return nil, ErrInvitationNotFound
+	}
+
+	// Expired invitations can be resent; accepted or revoked ones cannot.
+	if inv.AcceptedAt != nil || inv.RevokedAt != nil {
+		return nil, ErrInvitationNotPending
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	inv.TokenHash = utils.HashToken(token)
+	inv.ExpiresAt = time.Now().Add(s.cfg.InvitationExpiry)
+	inv.Status = models.InvitationStatusPending
+
+	if err := s.invitationRepo.UpdateToken(inv.ID, inv.TokenHash, inv.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	// Invitations of existing users ask them to join rather than to set up
+	// an account.
+	if existing, _ := s.userRepo.GetByEmail(inv.Email); existing != nil {
+		org, err := s.orgRepo.GetByID(inv.OrganizationID)
+		if err != nil {
+			return nil, ErrOrganizationNotFound
+		}
+		go s.emailService.SendOrganizationInvitationEmail(inv.Email, existing.DisplayName, org.Name, token, inv.ExpiresAt)
+	} else {
+		go s.emailService.SendInvitationEmail(inv.Email, inv.DisplayName, token, inv.ExpiresAt)
+	}
+
+	s.auditService.LogEvent(models.AuditEventInvitationResent, &resentBy, map[string]interface{}{
+		"invitation_id": inv.ID.String(),
+		"email":         inv.Email,
+	}, ip, userAgent)
+
+	return inv, nil
+}
+
+func (s *InvitationService) RevokeInvitation(id, orgID, revokedBy uuid.UUID, ip, userAgent string) error {
+	inv, err := s.invitationRepo.GetByID(id)
+	if err != nil || inv.OrganizationID != orgID {
+		return ErrInvitationNotFound
+	}
+
+	if inv.AcceptedAt != nil || inv.RevokedAt != nil {
+		return ErrInvitationNotPending
+	}
+
+	if err := s.invitationRepo.Revoke(inv.ID); err != nil {
+		return err
+	}
+
+	s.auditService.LogEvent(models.AuditEventInvitationRevoked, &revokedBy, map[string]interface{}{
+		"invitation_id": inv.ID.String(),
+		"email":         inv.Email,
+	}, ip, userAgent)
+
+	return nil
+}
+
+func (s *InvitationService) AcceptInvitation(req models.AcceptInvitationRequest, ip, userAgent string) (*models.User, error) {
+	inv, err := s.invitationRepo.GetByTokenHash(utils.HashToken(req.Token))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if inv.StatusAt(time.Now()) != models.InvitationStatusPending {
+		return nil, ErrInvalidToken
+	}
+
+	if valid, msg := utils.ValidatePassword(req.Password); !valid {
+		return nil, errors.New(msg)
+	}
+
+	existingUser, _ := s.userRepo.GetByEmail(inv.Email)
+	if existingUser != nil {
+		return nil, ErrDuplicateEmail
+	}
+
+	displayName := req.DisplayName
+	if displayName == "" {
+		displayName = inv.DisplayName
+	}
+	if displayName == "" {
+		displayName = strings.Split(inv.Email, "@")[0]
+	}
+
+	passwordHash, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		ID:           uuid.New(),
+		Email:        inv.Email,
+		PasswordHash: passwordHash,
+		DisplayName:  displayName,
+		IsActive:     true,
+		IsVerified:   true,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.orgRepo.AddMember(inv.OrganizationID, user.ID); err != nil {
+		return nil, err
+	}
+
+	assignedBy := user.ID
+	if inv.InvitedBy != nil {
+		assignedBy = *inv.InvitedBy
+	}
+	for _, roleID := range inv.RoleIDs {
+		if err := s.roleRepo.AssignRoleToUser(user.ID, inv.OrganizationID, roleID, assignedBy, nil, nil); err != nil {
+			return nil, err
+		}
+	}
+	if len(inv.RoleIDs) == 0 {
+		defaultRole, _ := s.roleRepo.GetByName("user")
+		if defaultRole != nil {
+			if err := s.roleRepo.AssignRoleToUser(user.ID, inv.OrganizationID, defaultRole.ID, assignedBy, nil, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.invitationRepo.MarkAccepted(inv.ID, user.ID); err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogEvent(models.AuditEventInvitationAccepted, &user.ID, map[string]interface{}{
+		"invitation_id":   inv.ID.String(),
+		"organization_id": inv.OrganizationID,
+		"role_ids":        inv.RoleIDs,
+	}, ip, userAgent)
+
+	return user, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/auth-service/internal/config"
+)
+
+func TestLoginThrottlerDelay(t *testing.T) {
+	throttler := &LoginThrottler{cfg: &config.Config{
+		LoginBackoffBase: time.Second,
+		LoginBackoffMax:  time.Minute,
+	}}
+
+	tests := []struct {
+		name      string
+		count     int
+		threshold int
+		want      time.Duration
+	}{
+		{"below threshold", 2, 3, 0},
+		{"at threshold", 3, 3, time.Second},
+		{"one past threshold", 4, 3, 2 * time.Second},
+		{"doubles each failure", 8, 3, 32 * time.Second},
+		{"capped at max", 9, 3, time.Minute},
+		{"huge exponent capped", 100, 3, time.Minute},
+		{"disabled threshold", 50, 0, 0},
+		{"negative threshold", 50, -1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := throttler.delay(tt.count, tt.threshold); got != tt.want {
+				t.Errorf("delay(%d, %d) = %s, want %s", tt.count, tt.threshold, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginThrottlerAccountKeyPattern(t *testing.T) {
+	throttler := &LoginThrottler{}
+	pattern := throttler.accountKeyPattern("account-1")
+
+	tests := []struct {
+		name string
+		key  string
+		want bool
+	}{
+		{"same account, IPv4", throttler.accountKey("account-1", "10.1.2.3"), true},
+		{"same account, IPv6", throttler.accountKey("account-1", "2001:db8::1"), true},
+		{"other account", throttler.accountKey("account-2", "10.1.2.3"), false},
+		{"IP key", throttler.ipKey("10.1.2.3"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := path.Match(pattern, tt.key)
+			if err != nil {
+				t.Fatalf("path.Match(%q): %v", pattern, err)
+			}
+			if got != tt.want {
+				t.Errorf("%q matches %q = %v, want %v", pattern, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginThrottlerResetAccountWithoutRedis(t *testing.T) {
+	throttler := &LoginThrottler{}
+	if err := throttler.ResetAccount("account-1"); err != nil {
+		t.Errorf("ResetAccount = %v, want nil", err)
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/auth-service/internal/models"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+var attributeKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_]{1,64}$`)
+
+type MetadataValidator struct {
+	schema *jsonschema.Schema
+}
+
+// NewMetadataValidator compiles the JSON Schema at schemaFile. With no file
+// configured, any pair of JSON objects is accepted.
+func NewMetadataValidator(schemaFile string) (*MetadataValidator, error) {
+	if schemaFile == "" {
+		return &MetadataValidator{}, nil
+	}
+
+	schema, err := jsonschema.Compile(schemaFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile metadata schema: %w", err)
+	}
+
+	return &MetadataValidator{schema: schema}, nil
+}
+
+func (v *MetadataValidator) Validate(metadata models.UserMetadata) error {
+	if v.schema == nil {
+		return nil
+	}
+
+	// The validator works on plain decoded JSON, so round-trip the struct.
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if err := v.schema.Validate(doc); err != nil {
+		if verr, ok := err.(*jsonschema.ValidationError); ok {
+			return fmt.Errorf("invalid metadata: %s", leafMessage(verr))
+		}
+		return fmt.Errorf("invalid metadata: %w", err)
+	}
+
+	return nil
+}
+
+func ValidateAttributeKey(key string) bool {
+	return attributeKeyRegex.MatchString(key)
+}
+
+func leafMessage(err *jsonschema.ValidationError) string {
+	for len(err.Causes) > 0 {
+		err = err.Causes[0]
+	}
+	return fmt.Sprintf("%s: %s", err.InstanceLocation, err.Message)
+}
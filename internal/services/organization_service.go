@@ -0,0 +1,212 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrOrganizationExists   = errors.New("organization slug already taken")
+	ErrInvalidOrgSlug       = errors.New("slug must be 2-63 lowercase letters, digits or hyphens")
+	ErrAlreadyOrgMember     = errors.New("user is already a member of this organization")
+	ErrSharedAccount        = errors.New("user also belongs to other organizations; only their membership and roles here can be changed")
+)
+
+// platformAccountPermission lets its holders in the default organization
+// change any account, wherever else it is a member.
+const platformAccountPermission = "users:write"
+
+var orgSlugRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+type OrganizationService struct {
+	orgRepo      *repository.OrganizationRepository
+	roleRepo     *repository.RoleRepository
+	tokenRepo    *repository.TokenRepository
+	denylist     *TokenDenylist
+	auditService *AuditService
+}
+
+func NewOrganizationService(
+	orgRepo *repository.OrganizationRepository,
+	roleRepo *repository.RoleRepository,
+	tokenRepo *repository.TokenRepository,
+	denylist *TokenDenylist,
+	auditService *AuditService,
+) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:      orgRepo,
+		roleRepo:     roleRepo,
+		tokenRepo:    tokenRepo,
+		denylist:     denylist,
+		auditService: auditService,
+	}
+}
+
+// requireMember hides users outside the caller's organization behind
+// ErrUserNotFound so admins cannot probe other tenants.
+func requireMember(orgRepo *repository.OrganizationRepository, orgID, userID uuid.UUID) error {
+	member, err := orgRepo.IsMember(orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// requireAccountControl guards changes to the account itself (email, active
+// flag, suspension, deletion), which every organization the user belongs to
+// sees. An organization's admins may only make them while the user belongs to
+// no other organization; past that, it takes a platform admin.
+func requireAccountControl(orgRepo *repository.OrganizationRepository, permissionRepo *repository.PermissionRepository, orgID, userID, actorID uuid.UUID) error {
+	memberships, err := orgRepo.ListMemberships(userID)
+	if err != nil {
+		return err
+	}
+	shared := false
+	for _, m := range memberships {
+		if m.Organization.ID != orgID {
+			shared = true
+			break
+		}
+	}
+	if !shared {
+		return nil
+	}
+
+	granted, err := permissionRepo.GetUserPermissions(actorID, models.DefaultOrganizationID)
+	if err != nil {
+		return err
+	}
+	for _, p := range granted {
+		if p == platformAccountPermission {
+			return nil
+		}
+	}
+	return ErrSharedAccount
+}
+
+func listMemberships(orgRepo *repository.OrganizationRepository, roleRepo *repository.RoleRepository, userID uuid.UUID) ([]models.OrganizationMembership, error) {
+	memberships, err := orgRepo.ListMemberships(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range memberships {
+		roles, err := roleRepo.GetUserRoles(userID, memberships[i].Organization.ID)
+		if err != nil {
+			return nil, err
+		}
+		memberships[i].Roles = roles
+	}
+	return memberships, nil
+}
+
+func (s *OrganizationService) ListMemberships(userID uuid.UUID) ([]models.OrganizationMembership, error) {
+	return listMemberships(s.orgRepo, s.roleRepo, userID)
+}
+
+func (s *OrganizationService) GetOrganization(id uuid.UUID) (*models.Organization, error) {
+	org, err := s.orgRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrOrganizationNotFound
+	}
+	return org, nil
+}
+
+// CreateOrganization creates an organization and makes the creator its first
+// member with the admin role there.
+func (s *OrganizationService) CreateOrganization(req models.CreateOrganizationRequest, createdBy uuid.UUID, ip, userAgent string) (*models.Organization, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !orgSlugRegex.MatchString(slug) {
+		return nil, ErrInvalidOrgSlug
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	if existing, _ := s.orgRepo.GetBySlug(slug); existing != nil {
+		return nil, ErrOrganizationExists
+	}
+
+	org := &models.Organization{
+		ID:        uuid.New(),
+		Slug:      slug,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+	if err := s.orgRepo.Create(org); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.orgRepo.AddMember(org.ID, createdBy); err != nil {
+		return nil, err
+	}
+
+	adminRole, _ := s.roleRepo.GetByName("admin")
+	if adminRole != nil {
+		if err := s.roleRepo.AssignRoleToUser(createdBy, org.ID, adminRole.ID, createdBy, nil, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	s.auditService.LogEvent(models.AuditEventOrganizationCreated, &createdBy, map[string]interface{}{
+		"organization_id": org.ID,
+		"slug":            org.Slug,
+	}, ip, userAgent)
+
+	return org, nil
+}
+
+// RemoveMember drops the user's membership and roles in the organization and
+// ends every session they have open in it. Sessions in other organizations
+// are left alone.
+func (s *OrganizationService) RemoveMember(orgID, userID, removedBy uuid.UUID, ip, userAgent string) error {
+	if err := requireOtherAdmin(s.roleRepo, s.auditService, orgID, userID, removedBy, "remove_member", ip, userAgent); err != nil {
+		return err
+	}
+
+	removed, err := s.orgRepo.RemoveMember(orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrUserNotFound
+	}
+
+	tokens, err := s.tokenRepo.ListActiveRefreshTokens(userID)
+	if err != nil {
+		return err
+	}
+
+	ended := make(map[uuid.UUID]bool)
+	for _, token := range tokens {
+		if token.OrganizationID != orgID || ended[token.SessionID] {
+			continue
+		}
+		if _, err := s.tokenRepo.RevokeSession(userID, token.SessionID); err != nil {
+			return err
+		}
+		if err := s.denylist.RevokeSession(token.SessionID); err != nil {
+			return err
+		}
+		ended[token.SessionID] = true
+	}
+
+	s.auditService.LogEvent(models.AuditEventOrganizationMemberRemoved, &userID, map[string]interface{}{
+		"organization_id":  orgID,
+		"removed_by":       removedBy.String(),
+		"sessions_revoked": len(ended),
+	}, ip, userAgent)
+
+	return nil
+}
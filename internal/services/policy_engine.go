@@ -0,0 +1,244 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+type PolicyEffect string
+
+const (
+	PolicyEffectAllow PolicyEffect = "allow"
+	PolicyEffectDeny  PolicyEffect = "deny"
+)
+
+// Policy is one rule of the policy file. Condition is a CEL expression over
+// the subject, resource and env maps that must evaluate to a bool.
+type Policy struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Actions     []string     `json:"actions"`
+	Effect      PolicyEffect `json:"effect"`
+	Condition   string       `json:"condition"`
+}
+
+type policyFile struct {
+	Policies []Policy `json:"policies"`
+}
+
+type compiledPolicy struct {
+	Policy
+	program cel.Program
+}
+
+// PolicyInput is what a policy condition can see. Values must be plain JSON
+// types, []string or time.Time so CEL can convert them.
+type PolicyInput struct {
+	Subject  map[string]interface{}
+	Resource map[string]interface{}
+	Env      map[string]interface{}
+}
+
+// PolicyDecision is the outcome of evaluating the policies for an action.
+// Effect is empty when no policy matched.
+type PolicyDecision struct {
+	Effect PolicyEffect `json:"effect,omitempty"`
+	Policy string       `json:"policy,omitempty"`
+}
+
+// PolicyEngine evaluates the rules of a JSON policy file. The file is
+// re-read by Reload whenever it changes on disk, so policies can be edited
+// without a restart; a file that fails to compile leaves the previous
+// policies in force.
+type PolicyEngine struct {
+	path string
+	env  *cel.Env
+
+	mu       sync.RWMutex
+	policies []compiledPolicy
+	modTime  time.Time
+}
+
+// NewPolicyEngine compiles the policy file at path. With no file configured
+// the engine holds no policies and every decision is left to RBAC.
+func NewPolicyEngine(path string) (*PolicyEngine, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("subject", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("resource", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("env", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Function("inCIDR",
+			cel.Overload("in_cidr_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(inCIDR),
+			),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &PolicyEngine{path: path, env: env}
+	if path == "" {
+		return e, nil
+	}
+
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload recompiles the policy file if it changed since the last load.
+func (e *PolicyEngine) Reload() error {
+	if e.path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	e.mu.RLock()
+	unchanged := info.ModTime().Equal(e.modTime)
+	e.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	data, err := os.ReadFile(e.path)
+	if err != nil {
+		return fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	policies, err := e.compile(data)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.policies = policies
+	e.modTime = info.ModTime()
+	e.mu.Unlock()
+
+	return nil
+}
+
+func (e *PolicyEngine) compile(data []byte) ([]compiledPolicy, error) {
+	var file policyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid policy file: %w", err)
+	}
+
+	compiled := make([]compiledPolicy, 0, len(file.Policies))
+	for _, p := range file.Policies {
+		if p.Name == "" || len(p.Actions) == 0 {
+			return nil, fmt.Errorf("policy %q: name and actions are required", p.Name)
+		}
+		if p.Effect != PolicyEffectAllow && p.Effect != PolicyEffectDeny {
+			return nil, fmt.Errorf("policy %q: effect must be allow or deny", p.Name)
+		}
+
+		ast, issues := e.env.Compile(p.Condition)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("policy %q: %w", p.Name, issues.Err())
+		}
+		if ast.OutputType() != cel.BoolType {
+			return nil, fmt.Errorf("policy %q: condition must be a bool expression", p.Name)
+		}
+
+		program, err := e.env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", p.Name, err)
+		}
+		compiled = append(compiled, compiledPolicy{Policy: p, program: program})
+	}
+
+	return compiled, nil
+}
+
+// Policies returns the rules currently in force.
+func (e *PolicyEngine) Policies() []Policy {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	policies := make([]Policy, len(e.policies))
+	for i, p := range e.policies {
+		policies[i] = p.Policy
+	}
+	return policies
+}
+
+// Evaluate applies the policies for an action. A matching deny wins over
+// any allow. A condition that fails to evaluate (for example because an
+// attribute is missing) counts as a match for deny rules and as no match
+// for allow rules, so mistakes fail closed.
+func (e *PolicyEngine) Evaluate(action string, input PolicyInput) PolicyDecision {
+	e.mu.RLock()
+	policies := e.policies
+	e.mu.RUnlock()
+
+	vars := map[string]interface{}{
+		"subject":  input.Subject,
+		"resource": input.Resource,
+		"env":      input.Env,
+	}
+
+	var allowed *compiledPolicy
+	for i := range policies {
+		p := &policies[i]
+		if !actionMatches(p.Actions, action) {
+			continue
+		}
+
+		out, _, err := p.program.Eval(vars)
+		matched := err == nil && out == types.True
+
+		switch p.Effect {
+		case PolicyEffectDeny:
+			if matched || err != nil {
+				return PolicyDecision{Effect: PolicyEffectDeny, Policy: p.Name}
+			}
+		case PolicyEffectAllow:
+			if matched && allowed == nil {
+				allowed = p
+			}
+		}
+	}
+
+	if allowed != nil {
+		return PolicyDecision{Effect: PolicyEffectAllow, Policy: allowed.Name}
+	}
+	return PolicyDecision{}
+}
+
+// actionMatches supports exact names, "*" and resource wildcards such as
+// "users:*".
+func actionMatches(patterns []string, action string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == action {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(action, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func inCIDR(lhs, rhs ref.Val) ref.Val {
+	ip := net.ParseIP(fmt.Sprint(lhs.Value()))
+	_, network, err := net.ParseCIDR(fmt.Sprint(rhs.Value()))
+	if ip == nil || err != nil {
+		return types.False
+	}
+	return types.Bool(network.Contains(ip))
+}
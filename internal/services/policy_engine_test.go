@@ -0,0 +1,216 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testPolicies = `{
+  "policies": [
+    {
+      "name": "no-self-administration",
+      "actions": ["users:write"],
+      "effect": "deny",
+      "condition": "resource.id == subject.id"
+    },
+    {
+      "name": "office-network",
+      "actions": ["reports:*"],
+      "effect": "allow",
+      "condition": "inCIDR(env.ip, '10.0.0.0/8')"
+    },
+    {
+      "name": "department-readers",
+      "actions": ["users:read"],
+      "effect": "allow",
+      "condition": "subject.attributes.department == resource.attributes.department"
+    },
+    {
+      "name": "missing-attribute-deny",
+      "actions": ["billing:read"],
+      "effect": "deny",
+      "condition": "subject.attributes.clearance < 3"
+    },
+    {
+      "name": "everything-for-auditors",
+      "actions": ["*"],
+      "effect": "allow",
+      "condition": "'auditor' in subject.roles"
+    }
+  ]
+}`
+
+func newTestPolicyEngine(t *testing.T, policies string) *PolicyEngine {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "policies.json")
+	if err := os.WriteFile(path, []byte(policies), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	engine, err := NewPolicyEngine(path)
+	if err != nil {
+		t.Fatalf("NewPolicyEngine: %v", err)
+	}
+	return engine
+}
+
+func TestPolicyEngineEvaluate(t *testing.T) {
+	engine := newTestPolicyEngine(t, testPolicies)
+
+	subject := func(id string, roles []string, attrs map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"id": id, "roles": roles, "attributes": attrs}
+	}
+
+	tests := []struct {
+		name   string
+		action string
+		input  PolicyInput
+		want   PolicyDecision
+	}{
+		{
+			name:   "deny matches",
+			action: "users:write",
+			input: PolicyInput{
+				Subject:  subject("u1", []string{"admin"}, map[string]interface{}{}),
+				Resource: map[string]interface{}{"id": "u1"},
+			},
+			want: PolicyDecision{Effect: PolicyEffectDeny, Policy: "no-self-administration"},
+		},
+		{
+			name:   "deny wins over a matching allow",
+			action: "users:write",
+			input: PolicyInput{
+				Subject:  subject("u1", []string{"auditor"}, map[string]interface{}{}),
+				Resource: map[string]interface{}{"id": "u1"},
+			},
+			want: PolicyDecision{Effect: PolicyEffectDeny, Policy: "no-self-administration"},
+		},
+		{
+			name:   "no policy matches",
+			action: "users:write",
+			input: PolicyInput{
+				Subject:  subject("u1", []string{"admin"}, map[string]interface{}{}),
+				Resource: map[string]interface{}{"id": "u2"},
+			},
+			want: PolicyDecision{},
+		},
+		{
+			name:   "wildcard action with custom function",
+			action: "reports:export",
+			input: PolicyInput{
+				Subject: subject("u1", []string{}, map[string]interface{}{}),
+				Env:     map[string]interface{}{"ip": "10.1.2.3"},
+			},
+			want: PolicyDecision{Effect: PolicyEffectAllow, Policy: "office-network"},
+		},
+		{
+			name:   "custom function outside the network",
+			action: "reports:export",
+			input: PolicyInput{
+				Subject: subject("u1", []string{}, map[string]interface{}{}),
+				Env:     map[string]interface{}{"ip": "192.168.1.1"},
+			},
+			want: PolicyDecision{},
+		},
+		{
+			name:   "first matching allow is reported",
+			action: "users:read",
+			input: PolicyInput{
+				Subject:  subject("u1", []string{"auditor"}, map[string]interface{}{"department": "sales"}),
+				Resource: map[string]interface{}{"id": "u2", "attributes": map[string]interface{}{"department": "sales"}},
+			},
+			want: PolicyDecision{Effect: PolicyEffectAllow, Policy: "department-readers"},
+		},
+		{
+			name:   "allow with a missing attribute does not match",
+			action: "users:read",
+			input: PolicyInput{
+				Subject:  subject("u1", []string{}, map[string]interface{}{}),
+				Resource: map[string]interface{}{"id": "u2", "attributes": map[string]interface{}{"department": "sales"}},
+			},
+			want: PolicyDecision{},
+		},
+		{
+			name:   "deny with a missing attribute fails closed",
+			action: "billing:read",
+			input: PolicyInput{
+				Subject: subject("u1", []string{}, map[string]interface{}{}),
+			},
+			want: PolicyDecision{Effect: PolicyEffectDeny, Policy: "missing-attribute-deny"},
+		},
+		{
+			name:   "catch-all action",
+			action: "anything:else",
+			input: PolicyInput{
+				Subject: subject("u1", []string{"auditor"}, map[string]interface{}{}),
+			},
+			want: PolicyDecision{Effect: PolicyEffectAllow, Policy: "everything-for-auditors"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := engine.Evaluate(tt.action, tt.input); got != tt.want {
+				t.Errorf("Evaluate(%q) = %+v, want %+v", tt.action, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyEngineCompileErrors(t *testing.T) {
+	engine := newTestPolicyEngine(t, `{"policies": []}`)
+
+	tests := []struct {
+		name     string
+		policies string
+	}{
+		{"invalid json", `{"policies": [`},
+		{"missing name", `{"policies": [{"actions": ["a"], "effect": "deny", "condition": "true"}]}`},
+		{"missing actions", `{"policies": [{"name": "p", "effect": "deny", "condition": "true"}]}`},
+		{"unknown effect", `{"policies": [{"name": "p", "actions": ["a"], "effect": "maybe", "condition": "true"}]}`},
+		{"syntax error", `{"policies": [{"name": "p", "actions": ["a"], "effect": "deny", "condition": "subject.id =="}]}`},
+		{"non-bool condition", `{"policies": [{"name": "p", "actions": ["a"], "effect": "deny", "condition": "'text'"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := engine.compile([]byte(tt.policies)); err == nil {
+				t.Error("compile succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestActionMatches(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		action   string
+		want     bool
+	}{
+		{[]string{"users:read"}, "users:read", true},
+		{[]string{"users:read"}, "users:write", false},
+		{[]string{"users:*"}, "users:write", true},
+		{[]string{"users:*"}, "roles:write", false},
+		{[]string{"*"}, "roles:write", true},
+		{[]string{"roles:assign", "users:*"}, "users:delete", true},
+		{nil, "users:read", false},
+	}
+
+	for _, tt := range tests {
+		if got := actionMatches(tt.patterns, tt.action); got != tt.want {
+			t.Errorf("actionMatches(%v, %q) = %v, want %v", tt.patterns, tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestShippedPoliciesCompile(t *testing.T) {
+	engine, err := NewPolicyEngine(filepath.Join("..", "..", "policies", "policies.json"))
+	if err != nil {
+		t.Fatalf("policies/policies.json: %v", err)
+	}
+	if len(engine.Policies()) == 0 {
+		t.Error("policies/policies.json has no policies")
+	}
+}
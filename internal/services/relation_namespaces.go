@@ -0,0 +1,221 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// The namespace configuration language describes, per object type, which
+// relations exist and how they are computed:
+//
+//	namespace folder {
+//	    relation owner
+//	    relation parent
+//	    relation editor = this | owner
+//	    relation viewer = this | editor | parent->viewer
+//	}
+//
+// A relation without "=" only holds its stored tuples. Otherwise it is the
+// union of its terms: "this" (stored tuples), another relation of the same
+// object (a computed userset), or "tupleset->relation", which follows every
+// object stored under tupleset and takes relation on it.
+
+type rewriteKind int
+
+const (
+	rewriteThis rewriteKind = iota
+	rewriteComputed
+	rewriteTupleToUserset
+)
+
+type rewriteTerm struct {
+	kind     rewriteKind
+	relation string
+	tupleset string
+}
+
+type relationNamespace struct {
+	name      string
+	relations map[string][]rewriteTerm
+}
+
+// RelationNamespaces is a parsed namespace configuration.
+type RelationNamespaces map[string]*relationNamespace
+
+// Namespaces backed by the RBAC tables rather than by tuples. They can only
+// appear as subjects: "user:<id>", "group:<id>#member" and
+// "role:<name>#member".
+const (
+	subjectNamespaceUser  = "user"
+	subjectNamespaceGroup = "group"
+	subjectNamespaceRole  = "role"
+)
+
+var relationIdentRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// LoadRelationNamespaces parses the namespace file at path. With no file
+// configured there are no namespaces and every relation API call fails.
+func LoadRelationNamespaces(path string) (RelationNamespaces, error) {
+	if path == "" {
+		return RelationNamespaces{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read relation namespaces: %w", err)
+	}
+	return ParseRelationNamespaces(data)
+}
+
+func ParseRelationNamespaces(data []byte) (RelationNamespaces, error) {
+	namespaces := RelationNamespaces{}
+	var current *relationNamespace
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+
+		switch {
+		case line == "}":
+			if current == nil {
+				return nil, fmt.Errorf("line %d: unexpected }", n)
+			}
+			current = nil
+
+		case strings.HasPrefix(line, "namespace "):
+			if current != nil {
+				return nil, fmt.Errorf("line %d: namespace %s is not closed", n, current.name)
+			}
+			name, ok := strings.CutSuffix(strings.TrimSpace(strings.TrimPrefix(line, "namespace ")), "{")
+			name = strings.TrimSpace(name)
+			if !ok || !relationIdentRegex.MatchString(name) {
+				return nil, fmt.Errorf("line %d: expected namespace <name> {", n)
+			}
+			if name == subjectNamespaceUser || name == subjectNamespaceGroup || name == subjectNamespaceRole {
+				return nil, fmt.Errorf("line %d: namespace %s is reserved", n, name)
+			}
+			if namespaces[name] != nil {
+				return nil, fmt.Errorf("line %d: namespace %s is defined twice", n, name)
+			}
+			current = &relationNamespace{name: name, relations: map[string][]rewriteTerm{}}
+			namespaces[name] = current
+
+		case strings.HasPrefix(line, "relation "):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: relation outside of a namespace", n)
+			}
+			name, rewrite, _ := strings.Cut(strings.TrimPrefix(line, "relation "), "=")
+			name = strings.TrimSpace(name)
+			if !relationIdentRegex.MatchString(name) {
+				return nil, fmt.Errorf("line %d: invalid relation name %q", n, name)
+			}
+			if _, exists := current.relations[name]; exists {
+				return nil, fmt.Errorf("line %d: relation %s is defined twice", n, name)
+			}
+			terms, err := parseRewrite(rewrite)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			current.relations[name] = terms
+
+		default:
+			return nil, fmt.Errorf("line %d: expected namespace, relation or }", n)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if current != nil {
+		return nil, fmt.Errorf("namespace %s is not closed", current.name)
+	}
+
+	if err := namespaces.validate(); err != nil {
+		return nil, err
+	}
+	return namespaces, nil
+}
+
+func parseRewrite(rewrite string) ([]rewriteTerm, error) {
+	if strings.TrimSpace(rewrite) == "" {
+		return []rewriteTerm{{kind: rewriteThis}}, nil
+	}
+
+	var terms []rewriteTerm
+	for _, part := range strings.Split(rewrite, "|") {
+		part = strings.TrimSpace(part)
+		if part == "this" {
+			terms = append(terms, rewriteTerm{kind: rewriteThis})
+			continue
+		}
+		if tupleset, relation, ok := strings.Cut(part, "->"); ok {
+			tupleset, relation = strings.TrimSpace(tupleset), strings.TrimSpace(relation)
+			if !relationIdentRegex.MatchString(tupleset) || !relationIdentRegex.MatchString(relation) {
+				return nil, fmt.Errorf("invalid term %q", part)
+			}
+			terms = append(terms, rewriteTerm{kind: rewriteTupleToUserset, tupleset: tupleset, relation: relation})
+			continue
+		}
+		if !relationIdentRegex.MatchString(part) {
+			return nil, fmt.Errorf("invalid term %q", part)
+		}
+		terms = append(terms, rewriteTerm{kind: rewriteComputed, relation: part})
+	}
+	return terms, nil
+}
+
+// validate checks that computed usersets and tuplesets name relations of
+// their own namespace. The relation taken after "->" belongs to whatever
+// namespace the related object is in, so it can only be resolved at check
+// time.
+func (ns RelationNamespaces) validate() error {
+	for _, namespace := range ns {
+		for name, terms := range namespace.relations {
+			for _, term := range terms {
+				var ref string
+				switch term.kind {
+				case rewriteComputed:
+					ref = term.relation
+				case rewriteTupleToUserset:
+					ref = term.tupleset
+				default:
+					continue
+				}
+				if _, ok := namespace.relations[ref]; !ok {
+					return fmt.Errorf("%s#%s refers to unknown relation %s", namespace.name, name, ref)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (ns RelationNamespaces) relation(namespace, relation string) ([]rewriteTerm, bool) {
+	n, ok := ns[namespace]
+	if !ok {
+		return nil, false
+	}
+	terms, ok := n.relations[relation]
+	return terms, ok
+}
+
+// acceptsTuples reports whether stored tuples count for the relation, i.e.
+// whether "this" is one of its terms.
+func (ns RelationNamespaces) acceptsTuples(namespace, relation string) bool {
+	terms, _ := ns.relation(namespace, relation)
+	for _, term := range terms {
+		if term.kind == rewriteThis {
+			return true
+		}
+	}
+	return false
+}
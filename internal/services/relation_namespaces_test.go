@@ -0,0 +1,142 @@
+package services
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRelationNamespaces(t *testing.T) {
+	config := `
+// shared folders
+namespace folder {
+    relation owner
+    relation parent
+    relation editor = this | owner // owners can edit
+    relation viewer = editor | parent->viewer
+}
+`
+	namespaces, err := ParseRelationNamespaces([]byte(config))
+	if err != nil {
+		t.Fatalf("ParseRelationNamespaces: %v", err)
+	}
+
+	folder := namespaces["folder"]
+	if folder == nil || len(namespaces) != 1 {
+		t.Fatalf("namespaces = %v, want only folder", namespaces)
+	}
+
+	want := map[string][]rewriteTerm{
+		"owner":  {{kind: rewriteThis}},
+		"parent": {{kind: rewriteThis}},
+		"editor": {{kind: rewriteThis}, {kind: rewriteComputed, relation: "owner"}},
+		"viewer": {
+			{kind: rewriteComputed, relation: "editor"},
+			{kind: rewriteTupleToUserset, tupleset: "parent", relation: "viewer"},
+		},
+	}
+	if !reflect.DeepEqual(folder.relations, want) {
+		t.Errorf("folder relations = %+v, want %+v", folder.relations, want)
+	}
+}
+
+func TestParseRelationNamespacesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:    "stray closing brace",
+			config:  "}",
+			wantErr: "line 1: unexpected }",
+		},
+		{
+			name:    "namespace not closed before the next",
+			config:  "namespace a {\nnamespace b {\n}",
+			wantErr: "line 2: namespace a is not closed",
+		},
+		{
+			name:    "namespace not closed at the end",
+			config:  "namespace a {\n    relation owner",
+			wantErr: "namespace a is not closed",
+		},
+		{
+			name:    "missing brace",
+			config:  "namespace a\n}",
+			wantErr: "line 1: expected namespace <name> {",
+		},
+		{
+			name:    "invalid namespace name",
+			config:  "namespace Folder {\n}",
+			wantErr: "line 1: expected namespace <name> {",
+		},
+		{
+			name:    "reserved namespace",
+			config:  "namespace group {\n}",
+			wantErr: "line 1: namespace group is reserved",
+		},
+		{
+			name:    "duplicate namespace",
+			config:  "namespace a {\n}\nnamespace a {\n}",
+			wantErr: "line 3: namespace a is defined twice",
+		},
+		{
+			name:    "relation outside a namespace",
+			config:  "relation owner",
+			wantErr: "line 1: relation outside of a namespace",
+		},
+		{
+			name:    "invalid relation name",
+			config:  "namespace a {\n    relation Owner\n}",
+			wantErr: `line 2: invalid relation name "Owner"`,
+		},
+		{
+			name:    "duplicate relation",
+			config:  "namespace a {\n    relation owner\n    relation owner\n}",
+			wantErr: "line 3: relation owner is defined twice",
+		},
+		{
+			name:    "invalid term",
+			config:  "namespace a {\n    relation owner\n    relation viewer = owner | ->x\n}",
+			wantErr: `line 3: invalid term "->x"`,
+		},
+		{
+			name:    "unknown line",
+			config:  "namespace a {\n    permission owner\n}",
+			wantErr: "line 2: expected namespace, relation or }",
+		},
+		{
+			name:    "unknown computed relation",
+			config:  "namespace a {\n    relation viewer = editor\n}",
+			wantErr: "a#viewer refers to unknown relation editor",
+		},
+		{
+			name:    "unknown tupleset",
+			config:  "namespace a {\n    relation viewer = parent->viewer\n}",
+			wantErr: "a#viewer refers to unknown relation parent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRelationNamespaces([]byte(tt.config))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShippedRelationNamespacesParse(t *testing.T) {
+	namespaces, err := LoadRelationNamespaces(filepath.Join("..", "..", "policies", "namespaces.rebac"))
+	if err != nil {
+		t.Fatalf("policies/namespaces.rebac: %v", err)
+	}
+	for _, name := range []string{"folder", "document"} {
+		if namespaces[name] == nil {
+			t.Errorf("namespace %s missing", name)
+		}
+	}
+}
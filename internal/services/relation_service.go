@@ -0,0 +1,339 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// maxRelationDepth bounds how many usersets a check may follow before it
+// gives up, so a deep or pathological graph cannot tie up the database.
+const maxRelationDepth = 25
+
+var (
+	ErrUnknownNamespace     = errors.New("unknown namespace")
+	ErrUnknownRelation      = errors.New("unknown relation")
+	ErrInvalidRelation      = errors.New("invalid relationship tuple")
+	ErrRelationGraphTooDeep = errors.New("relation graph is too deep to evaluate")
+)
+
+// RelationService stores relationship tuples and answers questions about
+// them. Users, group members and role holders come from the RBAC tables, so
+// an object can be shared with "group:<id>#member" or "role:<name>#member".
+type RelationService struct {
+	relationRepo *repository.RelationRepository
+	groupRepo    *repository.GroupRepository
+	roleRepo     *repository.RoleRepository
+	namespaces   RelationNamespaces
+	auditService *AuditService
+}
+
+func NewRelationService(
+	relationRepo *repository.RelationRepository,
+	groupRepo *repository.GroupRepository,
+	roleRepo *repository.RoleRepository,
+	namespaces RelationNamespaces,
+	auditService *AuditService,
+) *RelationService {
+	return &RelationService{
+		relationRepo: relationRepo,
+		groupRepo:    groupRepo,
+		roleRepo:     roleRepo,
+		namespaces:   namespaces,
+		auditService: auditService,
+	}
+}
+
+func (s *RelationService) ListTuples(orgID uuid.UUID, filter models.RelationTupleFilter) ([]models.RelationTuple, error) {
+	return s.relationRepo.List(orgID, filter)
+}
+
+// WriteTuples applies writes and deletes atomically. Nothing is changed if
+// any tuple is invalid.
+func (s *RelationService) WriteTuples(orgID uuid.UUID, req models.WriteRelationTuplesRequest, actorID uuid.UUID, ip, userAgent string) (int, int, error) {
+	if len(req.Writes) == 0 && len(req.Deletes) == 0 {
+		return 0, 0, fmt.Errorf("%w: writes or deletes are required", ErrInvalidRelation)
+	}
+	for _, t := range req.Writes {
+		if err := s.validateTuple(t); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	written, deleted, err := s.relationRepo.Apply(orgID, req.Writes, req.Deletes, actorID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	s.auditService.LogEvent(models.AuditEventRelationChange, &actorID, map[string]interface{}{
+		"organization_id": orgID,
+		"writes":          tupleStrings(req.Writes),
+		"deletes":         tupleStrings(req.Deletes),
+		"written":         written,
+		"deleted":         deleted,
+	}, ip, userAgent)
+
+	return written, deleted, nil
+}
+
+func (s *RelationService) validateTuple(t models.RelationTuple) error {
+	if _, ok := s.namespaces[t.Namespace]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownNamespace, t.Namespace)
+	}
+	if !s.namespaces.acceptsTuples(t.Namespace, t.Relation) {
+		return fmt.Errorf("%w: %s#%s does not hold stored tuples", ErrInvalidRelation, t.Namespace, t.Relation)
+	}
+	if t.ObjectID == "" || len(t.ObjectID) > 255 {
+		return fmt.Errorf("%w: object_id is required", ErrInvalidRelation)
+	}
+
+	subject := t.Subject
+	switch subject.Namespace {
+	case subjectNamespaceUser:
+		if _, err := uuid.Parse(subject.ID); err != nil || subject.Relation != "" {
+			return fmt.Errorf("%w: user subjects look like user:<uuid>", ErrInvalidRelation)
+		}
+	case subjectNamespaceGroup:
+		if _, err := uuid.Parse(subject.ID); err != nil || subject.Relation != "member" {
+			return fmt.Errorf("%w: group subjects look like group:<uuid>#member", ErrInvalidRelation)
+		}
+	case subjectNamespaceRole:
+		if subject.ID == "" || subject.Relation != "member" {
+			return fmt.Errorf("%w: role subjects look like role:<name>#member", ErrInvalidRelation)
+		}
+	default:
+		if _, ok := s.namespaces[subject.Namespace]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownNamespace, subject.Namespace)
+		}
+		if subject.ID == "" || len(subject.ID) > 255 {
+			return fmt.Errorf("%w: subject id is required", ErrInvalidRelation)
+		}
+		if _, ok := s.namespaces.relation(subject.Namespace, subject.Relation); subject.Relation != "" && !ok {
+			return fmt.Errorf("%w: %s#%s", ErrUnknownRelation, subject.Namespace, subject.Relation)
+		}
+	}
+	return nil
+}
+
+func (s *RelationService) requireRelation(namespace, relation string) error {
+	if _, ok := s.namespaces[namespace]; !ok {
+		return ErrUnknownNamespace
+	}
+	if _, ok := s.namespaces.relation(namespace, relation); !ok {
+		return ErrUnknownRelation
+	}
+	return nil
+}
+
+// Check reports whether the user has the relation to the object, directly,
+// through a group or role, or through the namespace's rewrite rules.
+func (s *RelationService) Check(orgID, userID uuid.UUID, namespace, objectID, relation string) (bool, error) {
+	if err := s.requireRelation(namespace, relation); err != nil {
+		return false, err
+	}
+	return s.newChecker(orgID, userID).check(namespace, objectID, relation, 0)
+}
+
+// ListObjects returns the objects of the namespace the user has the relation
+// to. Only objects that appear in at least one tuple are considered.
+func (s *RelationService) ListObjects(orgID, userID uuid.UUID, namespace, relation string) ([]string, error) {
+	if err := s.requireRelation(namespace, relation); err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.relationRepo.ListObjectIDs(orgID, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []string{}
+	for _, objectID := range candidates {
+		ok, err := s.newChecker(orgID, userID).check(namespace, objectID, relation, 0)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			objects = append(objects, objectID)
+		}
+	}
+	return objects, nil
+}
+
+// Expand returns the tree of usersets that make up the relation. Usersets
+// stored as subjects are listed but not expanded further; expand them with
+// another call.
+func (s *RelationService) Expand(orgID uuid.UUID, namespace, objectID, relation string) (*models.RelationTree, error) {
+	if err := s.requireRelation(namespace, relation); err != nil {
+		return nil, err
+	}
+
+	tree, err := s.expand(orgID, namespace, objectID, relation, 0, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	return &tree, nil
+}
+
+func (s *RelationService) expand(orgID uuid.UUID, namespace, objectID, relation string, depth int, onPath map[string]bool) (models.RelationTree, error) {
+	userset := models.RelationSubject{Namespace: namespace, ID: objectID, Relation: relation}.String()
+	node := models.RelationTree{Kind: "union", Userset: userset}
+
+	if depth > maxRelationDepth {
+		return node, ErrRelationGraphTooDeep
+	}
+	terms, ok := s.namespaces.relation(namespace, relation)
+	if !ok || onPath[userset] {
+		return node, nil
+	}
+	onPath[userset] = true
+	defer delete(onPath, userset)
+
+	for _, term := range terms {
+		switch term.kind {
+		case rewriteThis:
+			subjects, err := s.relationRepo.ListSubjects(orgID, namespace, objectID, relation)
+			if err != nil {
+				return node, err
+			}
+			node.Children = append(node.Children, models.RelationTree{Kind: "direct", Userset: userset, Subjects: subjects})
+
+		case rewriteComputed:
+			child, err := s.expand(orgID, namespace, objectID, term.relation, depth+1, onPath)
+			if err != nil {
+				return node, err
+			}
+			node.Children = append(node.Children, child)
+
+		case rewriteTupleToUserset:
+			related, err := s.relationRepo.ListSubjects(orgID, namespace, objectID, term.tupleset)
+			if err != nil {
+				return node, err
+			}
+			ttu := models.RelationTree{
+				Kind:    "tuple_to_userset",
+				Userset: models.RelationSubject{Namespace: namespace, ID: objectID, Relation: term.tupleset}.String(),
+			}
+			for _, object := range related {
+				child, err := s.expand(orgID, object.Namespace, object.ID, term.relation, depth+1, onPath)
+				if err != nil {
+					return node, err
+				}
+				ttu.Children = append(ttu.Children, child)
+			}
+			node.Children = append(node.Children, ttu)
+		}
+	}
+	return node, nil
+}
+
+// relationChecker evaluates one user's relations. Usersets already being
+// evaluated further up the path are skipped: relations are unions, so a
+// cycle can never add anything new.
+type relationChecker struct {
+	s      *RelationService
+	orgID  uuid.UUID
+	userID uuid.UUID
+	onPath map[string]bool
+}
+
+func (s *RelationService) newChecker(orgID, userID uuid.UUID) *relationChecker {
+	return &relationChecker{s: s, orgID: orgID, userID: userID, onPath: map[string]bool{}}
+}
+
+func (c *relationChecker) check(namespace, objectID, relation string, depth int) (bool, error) {
+	if depth > maxRelationDepth {
+		return false, ErrRelationGraphTooDeep
+	}
+	terms, ok := c.s.namespaces.relation(namespace, relation)
+	if !ok {
+		return false, nil
+	}
+
+	key := models.RelationSubject{Namespace: namespace, ID: objectID, Relation: relation}.String()
+	if c.onPath[key] {
+		return false, nil
+	}
+	c.onPath[key] = true
+	defer delete(c.onPath, key)
+
+	for _, term := range terms {
+		var found bool
+		var err error
+
+		switch term.kind {
+		case rewriteThis:
+			found, err = c.checkStored(namespace, objectID, relation, depth)
+		case rewriteComputed:
+			found, err = c.check(namespace, objectID, term.relation, depth+1)
+		case rewriteTupleToUserset:
+			found, err = c.checkRelated(namespace, objectID, term, depth)
+		}
+
+		if err != nil || found {
+			return found, err
+		}
+	}
+	return false, nil
+}
+
+func (c *relationChecker) checkStored(namespace, objectID, relation string, depth int) (bool, error) {
+	subjects, err := c.s.relationRepo.ListSubjects(c.orgID, namespace, objectID, relation)
+	if err != nil {
+		return false, err
+	}
+
+	for _, subject := range subjects {
+		found, err := c.includes(subject, depth)
+		if err != nil || found {
+			return found, err
+		}
+	}
+	return false, nil
+}
+
+func (c *relationChecker) checkRelated(namespace, objectID string, term rewriteTerm, depth int) (bool, error) {
+	related, err := c.s.relationRepo.ListSubjects(c.orgID, namespace, objectID, term.tupleset)
+	if err != nil {
+		return false, err
+	}
+
+	for _, object := range related {
+		found, err := c.check(object.Namespace, object.ID, term.relation, depth+1)
+		if err != nil || found {
+			return found, err
+		}
+	}
+	return false, nil
+}
+
+// includes reports whether the user is, or is part of, a stored subject.
+func (c *relationChecker) includes(subject models.RelationSubject, depth int) (bool, error) {
+	switch subject.Namespace {
+	case subjectNamespaceUser:
+		return subject.Relation == "" && subject.ID == c.userID.String(), nil
+	case subjectNamespaceGroup:
+		groupID, err := uuid.Parse(subject.ID)
+		if err != nil {
+			return false, nil
+		}
+		return c.s.groupRepo.HasMember(c.orgID, groupID, c.userID)
+	case subjectNamespaceRole:
+		return c.s.roleRepo.UserHasRole(c.userID, c.orgID, subject.ID)
+	}
+
+	if subject.Relation == "" {
+		return false, nil
+	}
+	return c.check(subject.Namespace, subject.ID, subject.Relation, depth+1)
+}
+
+func tupleStrings(tuples []models.RelationTuple) []string {
+	out := make([]string, len(tuples))
+	for i, t := range tuples {
+		out[i] = models.RelationSubject{Namespace: t.Namespace, ID: t.ObjectID, Relation: t.Relation}.String() + "@" + t.Subject.String()
+	}
+	return out
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// assignAnyRolePermission lets its holders assign every role. Everyone else
+// is limited to the roles in the management scopes of the roles they hold.
+const assignAnyRolePermission = "roles:assign"
+
+var ErrRoleNotManageable = errors.New("your roles do not allow managing this role")
+
+// requireManageable refuses to let actorID assign or unassign the role when
+// none of their roles in the organization covers it.
+func requireManageable(roleRepo *repository.RoleRepository, orgID, actorID uuid.UUID, role *models.Role) error {
+	allowed, err := roleRepo.CanManageRole(actorID, orgID, role.ID, assignAnyRolePermission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s", ErrRoleNotManageable, role.Name)
+	}
+	return nil
+}
@@ -2,28 +2,131 @@ package services
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/auth-service/internal/models"
 	"github.com/auth-service/internal/repository"
+	"github.com/google/uuid"
 )
 
 type RoleService struct {
-	roleRepo *repository.RoleRepository
+	roleRepo       *repository.RoleRepository
+	securityStamps *SecurityStamps
+	auditService   *AuditService
 }
 
-func NewRoleService(roleRepo *repository.RoleRepository) *RoleService {
-	return &RoleService{roleRepo: roleRepo}
+func NewRoleService(
+	roleRepo *repository.RoleRepository,
+	securityStamps *SecurityStamps,
+	auditService *AuditService,
+) *RoleService {
+	return &RoleService{
+		roleRepo:       roleRepo,
+		securityStamps: securityStamps,
+		auditService:   auditService,
+	}
 }
 
+var (
+	ErrRoleCycle  = errors.New("role inheritance would create a cycle")
+	ErrSystemRole = errors.New("system roles cannot be renamed or deleted")
+)
+
 func (s *RoleService) GetRole(id int) (*models.Role, error) {
-	return s.roleRepo.GetByID(id)
+	role, err := s.roleRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := s.roleRepo.InheritanceGraph()
+	if err != nil {
+		return nil, err
+	}
+	role.Inherits = graph[role.ID]
+
+	scopes, err := s.roleRepo.ManagementScopes()
+	if err != nil {
+		return nil, err
+	}
+	role.Manages = scopes[role.ID]
+
+	return role, nil
 }
 
 func (s *RoleService) ListRoles() ([]models.Role, error) {
-	return s.roleRepo.List()
+	roles, err := s.roleRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := s.roleRepo.InheritanceGraph()
+	if err != nil {
+		return nil, err
+	}
+	scopes, err := s.roleRepo.ManagementScopes()
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		roles[i].Inherits = graph[roles[i].ID]
+		roles[i].Manages = scopes[roles[i].ID]
+	}
+
+	return roles, nil
+}
+
+// validateInheritance checks that every inherited role exists and that
+// letting roleID inherit them would not make any role its own ancestor.
+func (s *RoleService) validateInheritance(roleID int, inherits []int) error {
+	graph, err := s.roleRepo.InheritanceGraph()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range inherits {
+		if id == roleID {
+			return ErrRoleCycle
+		}
+		if _, err := s.roleRepo.GetByID(id); err != nil {
+			return fmt.Errorf("inherited role %d not found", id)
+		}
+	}
+
+	graph[roleID] = inherits
+
+	visited := make(map[int]bool)
+	stack := append([]int(nil), inherits...)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == roleID {
+			return ErrRoleCycle
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, graph[id]...)
+	}
+
+	return nil
+}
+
+// validateManages checks that every role in a management scope exists.
+func (s *RoleService) validateManages(manages []int) error {
+	for _, id := range manages {
+		if _, err := s.roleRepo.GetByID(id); err != nil {
+			return fmt.Errorf("managed role %d not found", id)
+		}
+	}
+	return nil
 }
 
 func (s *RoleService) CreateRole(req models.CreateRoleRequest) (*models.Role, error) {
+	if req.MaxSessions != nil && *req.MaxSessions < 0 {
+		return nil, errors.New("max_sessions must not be negative")
+	}
+
 	existing, _ := s.roleRepo.GetByName(req.Name)
 	if existing != nil {
 		return nil, errors.New("role name already exists")
@@ -32,22 +135,53 @@ func (s *RoleService) CreateRole(req models.CreateRoleRequest) (*models.Role, er
 	role := &models.Role{
 		Name:        req.Name,
 		Description: req.Description,
+		MaxSessions: req.MaxSessions,
+	}
+
+	// A new role has no descendants yet, so only existence and
+	// self-reference need checking before it is created.
+	if err := s.validateInheritance(0, req.Inherits); err != nil {
+		return nil, err
+	}
+	if err := s.validateManages(req.Manages); err != nil {
+		return nil, err
 	}
 
 	if err := s.roleRepo.Create(role); err != nil {
 		return nil, err
 	}
 
+	if len(req.Inherits) > 0 {
+		if err := s.roleRepo.SetInheritedRoles(role.ID, req.Inherits); err != nil {
+			return nil, err
+		}
+		role.Inherits = req.Inherits
+	}
+
+	if len(req.Manages) > 0 {
+		if err := s.roleRepo.SetManagedRoles(role.ID, req.Manages); err != nil {
+			return nil, err
+		}
+		role.Manages = req.Manages
+	}
+
 	return role, nil
 }
 
-func (s *RoleService) UpdateRole(id int, req models.CreateRoleRequest) (*models.Role, error) {
+func (s *RoleService) UpdateRole(id int, req models.CreateRoleRequest, updatedBy uuid.UUID, ip, userAgent string) (*models.Role, error) {
 	role, err := s.roleRepo.GetByID(id)
 	if err != nil {
 		return nil, errors.New("role not found")
 	}
 
+	if req.MaxSessions != nil && *req.MaxSessions < -1 {
+		return nil, errors.New("max_sessions must be -1 or more")
+	}
+
 	if req.Name != role.Name {
+		if role.IsSystem {
+			return nil, ErrSystemRole
+		}
 		existing, _ := s.roleRepo.GetByName(req.Name)
 		if existing != nil {
 			return nil, errors.New("role name already exists")
@@ -57,18 +191,157 @@ func (s *RoleService) UpdateRole(id int, req models.CreateRoleRequest) (*models.
 	role.Name = req.Name
 	role.Description = req.Description
 
+	// MaxSessions is only changed when present; -1 removes the override so
+	// the global default applies again.
+	if req.MaxSessions != nil {
+		role.MaxSessions = req.MaxSessions
+		if *req.MaxSessions == -1 {
+			role.MaxSessions = nil
+		}
+	}
+
+	// Inherits and Manages are only replaced when present in the request,
+	// so clients that predate them don't wipe them on every update.
+	if req.Inherits != nil {
+		if err := s.validateInheritance(role.ID, req.Inherits); err != nil {
+			return nil, err
+		}
+	}
+	if req.Manages != nil {
+		if err := s.validateManages(req.Manages); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Inherits != nil {
+		if err := s.requireAdminAfter(role.ID, req.Inherits, updatedBy, "update_role", ip, userAgent); err != nil {
+			return nil, err
+		}
+	}
+
+	// Changing what the role inherits or manages changes what its holders
+	// may do, so their access tokens are made stale. Holders are collected
+	// before the change as well as after, since it can add or drop some.
+	definitionChanged := req.Inherits != nil || req.Manages != nil
+	var holders map[uuid.UUID]bool
+	if definitionChanged {
+		if holders, err = s.collectHolders(role.ID, nil); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.roleRepo.Update(role); err != nil {
 		return nil, err
 	}
 
-	return role, nil
+	if req.Inherits != nil {
+		if err := s.roleRepo.SetInheritedRoles(role.ID, req.Inherits); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Manages != nil {
+		if err := s.roleRepo.SetManagedRoles(role.ID, req.Manages); err != nil {
+			return nil, err
+		}
+	}
+
+	if definitionChanged {
+		if holders, err = s.collectHolders(role.ID, holders); err != nil {
+			return nil, err
+		}
+		if err := s.rotateHolders(holders); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetRole(role.ID)
 }
 
-func (s *RoleService) DeleteRole(id int) error {
-	_, err := s.roleRepo.GetByID(id)
+func (s *RoleService) DeleteRole(id int, deletedBy uuid.UUID, ip, userAgent string) error {
+	role, err := s.roleRepo.GetByID(id)
 	if err != nil {
 		return errors.New("role not found")
 	}
 
-	return s.roleRepo.Delete(id)
+	if role.IsSystem {
+		return ErrSystemRole
+	}
+
+	if err := s.requireAdminAfter(role.ID, nil, deletedBy, "delete_role", ip, userAgent); err != nil {
+		return err
+	}
+
+	holders, err := s.collectHolders(role.ID, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.roleRepo.Delete(id); err != nil {
+		return err
+	}
+
+	return s.rotateHolders(holders)
+}
+
+// collectHolders adds every user holding roleID in any organization to
+// holders, creating the set when nil.
+func (s *RoleService) collectHolders(roleID int, holders map[uuid.UUID]bool) (map[uuid.UUID]bool, error) {
+	if holders == nil {
+		holders = make(map[uuid.UUID]bool)
+	}
+	byOrg, err := s.roleRepo.ListRoleHolders(roleID)
+	if err != nil {
+		return nil, err
+	}
+	for _, userIDs := range byOrg {
+		for _, userID := range userIDs {
+			holders[userID] = true
+		}
+	}
+	return holders, nil
+}
+
+func (s *RoleService) rotateHolders(holders map[uuid.UUID]bool) error {
+	for userID := range holders {
+		if err := s.securityStamps.Rotate(userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requireAdminAfter refuses to change roleID to inherit only the given roles,
+// or to delete it (inherits nil), when that takes admin away from its holders
+// and leaves one of their organizations without any other active admin.
+func (s *RoleService) requireAdminAfter(roleID int, inherits []int, actorID uuid.UUID, operation, ip, userAgent string) error {
+	admin, err := s.roleRepo.GetByName(adminRoleName)
+	if err != nil {
+		return nil
+	}
+
+	graph, err := s.roleRepo.InheritanceGraph()
+	if err != nil {
+		return err
+	}
+	if !inheritsRole(graph, roleID, admin.ID) {
+		return nil
+	}
+	if inherits != nil {
+		graph[roleID] = inherits
+		if inheritsRole(graph, roleID, admin.ID) {
+			return nil
+		}
+	}
+
+	holders, err := s.roleRepo.ListRoleHolders(roleID)
+	if err != nil {
+		return err
+	}
+	for orgID, userIDs := range holders {
+		if err := requireAdminOutside(s.roleRepo, s.auditService, orgID, userIDs, actorID, operation, ip, userAgent); err != nil {
+			return err
+		}
+	}
+	return nil
 }
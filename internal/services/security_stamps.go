@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/auth-service/internal/config"
+	"github.com/auth-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// SecurityStamps tracks each user's security stamp. Access tokens carry the
+// stamp they were issued with, and a token whose stamp no longer matches
+// must be refreshed, so rotating the stamp makes role changes and
+// deactivations apply to tokens already handed out. Stamps are cached in
+// Redis so checking them does not cost a database query per request.
+type SecurityStamps struct {
+	redis    *redis.Client
+	userRepo *repository.UserRepository
+	ttl      time.Duration
+}
+
+func NewSecurityStamps(redisClient *redis.Client, userRepo *repository.UserRepository, cfg *config.Config) *SecurityStamps {
+	return &SecurityStamps{
+		redis:    redisClient,
+		userRepo: userRepo,
+		ttl:      cfg.AccessTokenExpiry,
+	}
+}
+
+// Current returns the user's stamp, from the cache when possible.
+func (s *SecurityStamps) Current(userID uuid.UUID) (string, error) {
+	ctx := context.Background()
+
+	if s.redis != nil {
+		if stamp, err := s.redis.Get(ctx, s.key(userID)).Result(); err == nil {
+			return stamp, nil
+		}
+	}
+
+	stamp, err := s.userRepo.GetSecurityStamp(userID)
+	if err != nil {
+		return "", err
+	}
+
+	if s.redis != nil {
+		s.redis.Set(ctx, s.key(userID), stamp, s.ttl)
+	}
+	return stamp, nil
+}
+
+// Rotate gives the user a new stamp, which makes every access token issued
+// to them so far stale.
+func (s *SecurityStamps) Rotate(userID uuid.UUID) error {
+	stamp := uuid.NewString()
+	if err := s.userRepo.SetSecurityStamp(userID, stamp); err != nil {
+		return err
+	}
+
+	if s.redis != nil {
+		return s.redis.Set(context.Background(), s.key(userID), stamp, s.ttl).Err()
+	}
+	return nil
+}
+
+func (s *SecurityStamps) key(userID uuid.UUID) string {
+	return "security_stamp:" + userID.String()
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/auth-service/internal/models"
+	"github.com/auth-service/internal/repository"
+	"github.com/auth-service/internal/utils"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+type SessionService struct {
+	userRepo     *repository.UserRepository
+	orgRepo      *repository.OrganizationRepository
+	tokenRepo    *repository.TokenRepository
+	denylist     *TokenDenylist
+	auditService *AuditService
+}
+
+func NewSessionService(
+	userRepo *repository.UserRepository,
+	orgRepo *repository.OrganizationRepository,
+	tokenRepo *repository.TokenRepository,
+	denylist *TokenDenylist,
+	auditService *AuditService,
+) *SessionService {
+	return &SessionService{
+		userRepo:     userRepo,
+		orgRepo:      orgRepo,
+		tokenRepo:    tokenRepo,
+		denylist:     denylist,
+		auditService: auditService,
+	}
+}
+
+func (s *SessionService) ListSessions(userID, currentSessionID uuid.UUID) ([]models.Session, error) {
+	tokens, err := s.tokenRepo.ListActiveRefreshTokens(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSessions(tokens, currentSessionID), nil
+}
+
+func toSessions(tokens []models.RefreshToken, currentSessionID uuid.UUID) []models.Session {
+	sessions := make([]models.Session, 0, len(tokens))
+	for _, token := range tokens {
+		ua := utils.ParseUserAgent(token.UserAgent)
+		sessions = append(sessions, models.Session{
+			ID:             token.SessionID,
+			OrganizationID: token.OrganizationID,
+			Device: models.DeviceInfo{
+				Browser:        ua.Browser,
+				BrowserVersion: ua.BrowserVersion,
+				OS:             ua.OS,
+				Platform:       ua.Platform,
+				Mobile:         ua.Mobile,
+				Bot:            ua.Bot,
+			},
+			UserAgent:    token.UserAgent,
+			IPAddress:    token.IPAddress,
+			Location:     token.Geo,
+			StartedAt:    token.SessionStartedAt,
+			LastActiveAt: token.IssuedAt,
+			ExpiresAt:    token.ExpiresAt,
+			Current:      token.SessionID == currentSessionID,
+		})
+	}
+
+	return sessions
+}
+
+// orgSessionTokens returns the user's active refresh tokens for sessions in
+// the organization. Admins only ever see and end those; sessions the user has
+// open in other organizations are none of their business.
+func (s *SessionService) orgSessionTokens(orgID, userID uuid.UUID) ([]models.RefreshToken, error) {
+	if err := requireMember(s.orgRepo, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.tokenRepo.ListActiveRefreshTokens(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	inOrg := tokens[:0]
+	for _, token := range tokens {
+		if token.OrganizationID == orgID {
+			inOrg = append(inOrg, token)
+		}
+	}
+	return inOrg, nil
+}
+
+func (s *SessionService) ListUserSessions(orgID, userID, currentSessionID uuid.UUID) ([]models.Session, error) {
+	tokens, err := s.orgSessionTokens(orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSessions(tokens, currentSessionID), nil
+}
+
+func (s *SessionService) RevokeSession(userID, sessionID, revokedBy uuid.UUID, ip, userAgent string) error {
+	revoked, err := s.tokenRepo.RevokeSession(userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+
+	if err := s.denylist.RevokeSession(sessionID); err != nil {
+		return err
+	}
+
+	s.auditService.LogEvent(models.AuditEventSessionRevoked, &userID, map[string]interface{}{
+		"session_id": sessionID,
+		"revoked_by": revokedBy.String(),
+	}, ip, userAgent)
+
+	return nil
+}
+
+// RevokeUserSession is the admin variant of RevokeSession, limited to
+// sessions of users in the admin's organization that are open in it.
+func (s *SessionService) RevokeUserSession(orgID, userID, sessionID, revokedBy uuid.UUID, ip, userAgent string) error {
+	tokens, err := s.orgSessionTokens(orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		if token.SessionID == sessionID {
+			return s.RevokeSession(userID, sessionID, revokedBy, ip, userAgent)
+		}
+	}
+	return ErrSessionNotFound
+}
+
+// RevokeAllSessions ends every session the user has open in the admin's
+// organization. Sessions in other organizations are left alone.
+func (s *SessionService) RevokeAllSessions(orgID, userID, revokedBy uuid.UUID, ip, userAgent string) error {
+	tokens, err := s.orgSessionTokens(orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	ended := make(map[uuid.UUID]bool)
+	for _, token := range tokens {
+		if ended[token.SessionID] {
+			continue
+		}
+		if _, err := s.tokenRepo.RevokeSession(userID, token.SessionID); err != nil {
+			return err
+		}
+		if err := s.denylist.RevokeSession(token.SessionID); err != nil {
+			return err
+		}
+		ended[token.SessionID] = true
+	}
+
+	s.auditService.LogEvent(models.AuditEventSessionRevoked, &userID, map[string]interface{}{
+		"all":              true,
+		"organization_id":  orgID,
+		"sessions_revoked": len(ended),
+		"revoked_by":       revokedBy.String(),
+	}, ip, userAgent)
+
+	return nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/auth-service/internal/models"
@@ -10,39 +11,76 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	ErrUserNotSuspended  = errors.New("user is not suspended")
+	ErrCannotSuspendSelf = errors.New("cannot suspend your own account")
+
+	ErrInvalidAttributeFilter = errors.New("invalid attribute filter")
+)
+
 type UserService struct {
-	userRepo     *repository.UserRepository
-	roleRepo     *repository.RoleRepository
-	auditService *AuditService
+	userRepo          *repository.UserRepository
+	roleRepo          *repository.RoleRepository
+	orgRepo           *repository.OrganizationRepository
+	permissionRepo    *repository.PermissionRepository
+	tokenRepo         *repository.TokenRepository
+	securityStamps    *SecurityStamps
+	loginThrottler    *LoginThrottler
+	auditService      *AuditService
+	metadataValidator *MetadataValidator
 }
 
 func NewUserService(
 	userRepo *repository.UserRepository,
 	roleRepo *repository.RoleRepository,
+	orgRepo *repository.OrganizationRepository,
+	permissionRepo *repository.PermissionRepository,
+	tokenRepo *repository.TokenRepository,
+	securityStamps *SecurityStamps,
+	loginThrottler *LoginThrottler,
 	auditService *AuditService,
+	metadataValidator *MetadataValidator,
 ) *UserService {
 	return &UserService{
-		userRepo:     userRepo,
-		roleRepo:     roleRepo,
-		auditService: auditService,
+		userRepo:          userRepo,
+		roleRepo:          roleRepo,
+		orgRepo:           orgRepo,
+		permissionRepo:    permissionRepo,
+		tokenRepo:         tokenRepo,
+		securityStamps:    securityStamps,
+		loginThrottler:    loginThrottler,
+		auditService:      auditService,
+		metadataValidator: metadataValidator,
 	}
 }
 
-func (s *UserService) GetUser(id uuid.UUID) (*models.UserWithRoles, error) {
+// Every admin operation below is confined to the caller's organization:
+// users outside it are reported as not found. Changes to the account itself
+// additionally go through requireAccountControl.
+
+func (s *UserService) GetUser(id, orgID uuid.UUID) (*models.UserWithRoles, error) {
+	if err := requireMember(s.orgRepo, orgID, id); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetByID(id)
 	if err != nil {
 		return nil, ErrUserNotFound
 	}
 
-	roles, _ := s.roleRepo.GetUserRoles(id)
+	roles, _ := s.roleRepo.GetUserRoles(id, orgID)
+	grants, _ := s.roleRepo.ListUserGrants(id, orgID)
 
 	return &models.UserWithRoles{
-		User:  *user,
-		Roles: roles,
+		User:   *user,
+		Roles:  roles,
+		Grants: grants,
 	}, nil
 }
 
-func (s *UserService) ListUsers(page, perPage int, search string) (*models.PaginatedResponse, error) {
+func (s *UserService) ListUsers(orgID uuid.UUID, page, perPage int, filter models.UserFilter) (*models.PaginatedResponse, error) {
+	filter.OrganizationID = orgID
+
 	if page < 1 {
 		page = 1
 	}
@@ -50,7 +88,13 @@ func (s *UserService) ListUsers(page, perPage int, search string) (*models.Pagin
 		perPage = 20
 	}
 
-	users, total, err := s.userRepo.List(page, perPage, search)
+	for key := range filter.Attributes {
+		if !ValidateAttributeKey(key) {
+			return nil, fmt.Errorf("%w %q", ErrInvalidAttributeFilter, key)
+		}
+	}
+
+	users, total, err := s.userRepo.List(page, perPage, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +113,7 @@ func (s *UserService) ListUsers(page, perPage int, search string) (*models.Pagin
 	}, nil
 }
 
-func (s *UserService) CreateUser(req models.CreateUserRequest, createdBy uuid.UUID, ip, userAgent string) (*models.User, error) {
+func (s *UserService) CreateUser(req models.CreateUserRequest, orgID, createdBy uuid.UUID, ip, userAgent string) (*models.User, error) {
 	email := utils.SanitizeEmail(req.Email)
 
 	if !utils.ValidateEmail(email) {
@@ -85,6 +129,26 @@ func (s *UserService) CreateUser(req models.CreateUserRequest, createdBy uuid.UU
 		return nil, ErrDuplicateEmail
 	}
 
+	username, err := normalizeUsername(s.userRepo, req.Username, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	phone, err := normalizePhone(s.userRepo, req.Phone, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, roleID := range req.RoleIDs {
+		role, err := s.roleRepo.GetByID(roleID)
+		if err != nil {
+			return nil, ErrRoleNotFound
+		}
+		if err := requireManageable(s.roleRepo, orgID, createdBy, role); err != nil {
+			return nil, err
+		}
+	}
+
 	passwordHash, err := utils.HashPassword(req.Password)
 	if err != nil {
 		return nil, err
@@ -93,6 +157,8 @@ func (s *UserService) CreateUser(req models.CreateUserRequest, createdBy uuid.UU
 	user := &models.User{
 		ID:           uuid.New(),
 		Email:        email,
+		Username:     username,
+		Phone:        phone,
 		PasswordHash: passwordHash,
 		DisplayName:  req.DisplayName,
 		IsActive:     true,
@@ -105,14 +171,25 @@ func (s *UserService) CreateUser(req models.CreateUserRequest, createdBy uuid.UU
 		return nil, err
 	}
 
+	if _, err := s.orgRepo.AddMember(orgID, user.ID); err != nil {
+		return nil, err
+	}
+
 	for _, roleID := range req.RoleIDs {
-		s.roleRepo.AssignRoleToUser(user.ID, roleID, createdBy)
+		s.roleRepo.AssignRoleToUser(user.ID, orgID, roleID, createdBy, nil, nil)
 	}
 
 	return user, nil
 }
 
-func (s *UserService) UpdateUser(id uuid.UUID, req models.UpdateUserRequest, updatedBy uuid.UUID, ip, userAgent string) (*models.User, error) {
+func (s *UserService) UpdateUser(id, orgID uuid.UUID, req models.UpdateUserRequest, updatedBy uuid.UUID, ip, userAgent string) (*models.User, error) {
+	if err := requireMember(s.orgRepo, orgID, id); err != nil {
+		return nil, err
+	}
+	if err := requireAccountControl(s.orgRepo, s.permissionRepo, orgID, id, updatedBy); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetByID(id)
 	if err != nil {
 		return nil, ErrUserNotFound
@@ -130,68 +207,391 @@ func (s *UserService) UpdateUser(id uuid.UUID, req models.UpdateUserRequest, upd
 		user.Email = email
 	}
 
+	if req.Username != nil {
+		username, err := normalizeUsername(s.userRepo, *req.Username, id)
+		if err != nil {
+			return nil, err
+		}
+		user.Username = username
+	}
+
+	if req.Phone != nil {
+		phone, err := normalizePhone(s.userRepo, *req.Phone, id)
+		if err != nil {
+			return nil, err
+		}
+		user.Phone = phone
+	}
+
 	if req.DisplayName != nil {
 		user.DisplayName = *req.DisplayName
 	}
 
+	activeChanged := false
 	if req.IsActive != nil {
+		if user.IsActive && !*req.IsActive {
+			if err := requireOtherAdminEverywhere(s.orgRepo, s.roleRepo, s.auditService, id, updatedBy, "deactivate", ip, userAgent); err != nil {
+				return nil, err
+			}
+		}
+		activeChanged = user.IsActive != *req.IsActive
 		user.IsActive = *req.IsActive
 	}
 
+	if req.Metadata != nil {
+		metadata := user.Metadata
+		if req.Metadata.User != nil {
+			metadata.User = req.Metadata.User
+		}
+		if req.Metadata.Admin != nil {
+			metadata.Admin = req.Metadata.Admin
+		}
+		if err := s.metadataValidator.Validate(metadata); err != nil {
+			return nil, err
+		}
+		user.Metadata = metadata
+	}
+
 	if err := s.userRepo.Update(user); err != nil {
 		return nil, err
 	}
 
+	if activeChanged {
+		if err := s.securityStamps.Rotate(id); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Metadata != nil {
+		if err := s.userRepo.UpdateMetadata(user.ID, user.Metadata); err != nil {
+			return nil, err
+		}
+
+		s.auditService.LogEvent(models.AuditEventMetadataUpdated, &id, map[string]interface{}{
+			"updated_by": updatedBy.String(),
+			"user":       req.Metadata.User != nil,
+			"admin":      req.Metadata.Admin != nil,
+		}, ip, userAgent)
+	}
+
+	return user, nil
+}
+
+func (s *UserService) UpdateOwnMetadata(userID uuid.UUID, attributes map[string]interface{}, ip, userAgent string) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if attributes == nil {
+		attributes = map[string]interface{}{}
+	}
+
+	metadata := user.Metadata
+	metadata.User = attributes
+	if err := s.metadataValidator.Validate(metadata); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateMetadata(userID, metadata); err != nil {
+		return nil, err
+	}
+	user.Metadata = metadata
+
+	s.auditService.LogEvent(models.AuditEventMetadataUpdated, &userID, map[string]interface{}{
+		"updated_by": userID.String(),
+		"user":       true,
+		"admin":      false,
+	}, ip, userAgent)
+
 	return user, nil
 }
 
-func (s *UserService) DeleteUser(id uuid.UUID) error {
+func (s *UserService) DeleteUser(id, orgID, deletedBy uuid.UUID, ip, userAgent string) error {
+	if err := requireMember(s.orgRepo, orgID, id); err != nil {
+		return err
+	}
+	if err := requireAccountControl(s.orgRepo, s.permissionRepo, orgID, id, deletedBy); err != nil {
+		return err
+	}
+
 	_, err := s.userRepo.GetByID(id)
 	if err != nil {
 		return ErrUserNotFound
 	}
 
+	if err := requireOtherAdminEverywhere(s.orgRepo, s.roleRepo, s.auditService, id, deletedBy, "delete", ip, userAgent); err != nil {
+		return err
+	}
+
 	return s.userRepo.Delete(id)
 }
 
-func (s *UserService) AssignRole(userID uuid.UUID, roleID int, assignedBy uuid.UUID, ip, userAgent string) error {
-	_, err := s.userRepo.GetByID(userID)
-	if err != nil {
-		return ErrUserNotFound
+func (s *UserService) AssignRole(userID, orgID uuid.UUID, req models.AssignRoleRequest, assignedBy uuid.UUID, ip, userAgent string) error {
+	if err := requireMember(s.orgRepo, orgID, userID); err != nil {
+		return err
 	}
 
-	_, err = s.roleRepo.GetByID(roleID)
+	role, err := s.roleRepo.GetByID(req.RoleID)
 	if err != nil {
 		return errors.New("role not found")
 	}
 
-	if err := s.roleRepo.AssignRoleToUser(userID, roleID, assignedBy); err != nil {
+	if err := requireManageable(s.roleRepo, orgID, assignedBy, role); err != nil {
+		return err
+	}
+
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return errors.New("expires_at must be in the future")
+		}
+		if req.StartsAt != nil && !req.ExpiresAt.After(*req.StartsAt) {
+			return errors.New("expires_at must be after starts_at")
+		}
+	}
+
+	if err := s.roleRepo.AssignRoleToUser(userID, orgID, req.RoleID, assignedBy, req.StartsAt, req.ExpiresAt); err != nil {
+		return err
+	}
+
+	if err := s.securityStamps.Rotate(userID); err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"action":          "assign",
+		"role_id":         req.RoleID,
+		"organization_id": orgID,
+		"assigned_by":     assignedBy.String(),
+	}
+	if req.StartsAt != nil {
+		payload["starts_at"] = req.StartsAt.UTC().Format(time.RFC3339)
+	}
+	if req.ExpiresAt != nil {
+		payload["expires_at"] = req.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	s.auditService.LogEvent(models.AuditEventRoleChange, &userID, payload, ip, userAgent)
+
+	return nil
+}
+
+// ProcessExpiredRoleGrants removes role assignments whose expires_at has
+// passed. Expired grants are already ignored when roles are resolved; this
+// cleans them up and records that they lapsed. A lapsed admin grant that left
+// its organization without any active admin is kept and flagged instead, so
+// the record of who last administered it survives until someone takes over.
+func (s *UserService) ProcessExpiredRoleGrants() error {
+	now := time.Now()
+	expired, err := s.roleRepo.ListExpiredGrants(now)
+	if err != nil {
+		return err
+	}
+
+	// A grant that fails is reported but doesn't keep the others from
+	// expiring; it is retried on the next run.
+	var errs []error
+	for _, grant := range expired {
+		if err := s.expireRoleGrant(grant, now); err != nil {
+			errs = append(errs, fmt.Errorf("expire role %d for user %s in organization %s: %w", grant.RoleID, grant.UserID, grant.OrganizationID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *UserService) expireRoleGrant(grant models.UserRole, now time.Time) error {
+	grantsAdmin, err := roleGrants(s.roleRepo, grant.RoleID, adminRoleName)
+	if err != nil {
+		return err
+	}
+	if grantsAdmin {
+		err := requireAdminOutside(s.roleRepo, s.auditService, grant.OrganizationID, []uuid.UUID{grant.UserID}, uuid.Nil, "expire_role_grant", "", "system")
+		if err == ErrLastAdmin {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	deleted, err := s.roleRepo.DeleteExpiredGrant(grant.UserID, grant.OrganizationID, grant.RoleID, now)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return nil
+	}
+
+	if err := s.securityStamps.Rotate(grant.UserID); err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"action":          "expire",
+		"role_id":         grant.RoleID,
+		"organization_id": grant.OrganizationID,
+		"expired_at":      grant.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+	if grant.AssignedBy != nil {
+		payload["assigned_by"] = grant.AssignedBy.String()
+	}
+	s.auditService.LogEvent(models.AuditEventRoleChange, &grant.UserID, payload, "", "system")
+
+	return nil
+}
+
+func (s *UserService) UnassignRole(userID, orgID uuid.UUID, roleID int, removedBy uuid.UUID, ip, userAgent string) error {
+	if err := requireMember(s.orgRepo, orgID, userID); err != nil {
+		return err
+	}
+
+	role, err := s.roleRepo.GetByID(roleID)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+
+	if err := requireManageable(s.roleRepo, orgID, removedBy, role); err != nil {
+		return err
+	}
+
+	grantsAdmin, err := roleGrants(s.roleRepo, roleID, adminRoleName)
+	if err != nil {
+		return err
+	}
+	if grantsAdmin {
+		if err := requireOtherAdmin(s.roleRepo, s.auditService, orgID, userID, removedBy, "unassign_role", ip, userAgent); err != nil {
+			return err
+		}
+	}
+
+	if err := s.roleRepo.UnassignRoleFromUser(userID, orgID, roleID); err != nil {
+		return err
+	}
+
+	if err := s.securityStamps.Rotate(userID); err != nil {
 		return err
 	}
 
 	s.auditService.LogEvent(models.AuditEventRoleChange, &userID, map[string]interface{}{
-		"action":      "assign",
-		"role_id":     roleID,
-		"assigned_by": assignedBy.String(),
+		"action":          "unassign",
+		"role_id":         roleID,
+		"organization_id": orgID,
+		"removed_by":      removedBy.String(),
 	}, ip, userAgent)
 
 	return nil
 }
 
-func (s *UserService) UnassignRole(userID uuid.UUID, roleID int, removedBy uuid.UUID, ip, userAgent string) error {
-	if err := s.roleRepo.UnassignRoleFromUser(userID, roleID); err != nil {
+func (s *UserService) SuspendUser(id, orgID uuid.UUID, req models.SuspendUserRequest, suspendedBy uuid.UUID, ip, userAgent string) (*models.User, error) {
+	if id == suspendedBy {
+		return nil, ErrCannotSuspendSelf
+	}
+
+	if err := requireMember(s.orgRepo, orgID, id); err != nil {
+		return nil, err
+	}
+	if err := requireAccountControl(s.orgRepo, s.permissionRepo, orgID, id, suspendedBy); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.GetByID(id); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if req.Until != nil && !req.Until.After(time.Now()) {
+		return nil, errors.New("suspension end time must be in the future")
+	}
+
+	if err := requireOtherAdminEverywhere(s.orgRepo, s.roleRepo, s.auditService, id, suspendedBy, "suspend", ip, userAgent); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Suspend(id, req.Reason, req.Until, suspendedBy); err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenRepo.RevokeAllUserTokens(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.securityStamps.Rotate(id); err != nil {
+		return nil, err
+	}
+
+	payload := map[string]interface{}{
+		"reason":       req.Reason,
+		"suspended_by": suspendedBy.String(),
+	}
+	if req.Until != nil {
+		payload["until"] = req.Until.UTC().Format(time.RFC3339)
+	}
+	s.auditService.LogEvent(models.AuditEventAccountSuspended, &id, payload, ip, userAgent)
+
+	return s.userRepo.GetByID(id)
+}
+
+func (s *UserService) UnsuspendUser(id, orgID, unsuspendedBy uuid.UUID, ip, userAgent string) error {
+	if err := requireMember(s.orgRepo, orgID, id); err != nil {
+		return err
+	}
+	if err := requireAccountControl(s.orgRepo, s.permissionRepo, orgID, id, unsuspendedBy); err != nil {
 		return err
 	}
 
-	s.auditService.LogEvent(models.AuditEventRoleChange, &userID, map[string]interface{}{
-		"action":     "unassign",
-		"role_id":    roleID,
-		"removed_by": removedBy.String(),
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if user.SuspendedAt == nil {
+		return ErrUserNotSuspended
+	}
+
+	if err := s.userRepo.ClearSuspension(id); err != nil {
+		return err
+	}
+
+	s.auditService.LogEvent(models.AuditEventAccountUnsuspended, &id, map[string]interface{}{
+		"unsuspended_by": unsuspendedBy.String(),
 	}, ip, userAgent)
 
 	return nil
 }
 
+func (s *UserService) UnlockUser(id, orgID, unlockedBy uuid.UUID, ip, userAgent string) error {
+	if err := requireMember(s.orgRepo, orgID, id); err != nil {
+		return err
+	}
+	if err := requireAccountControl(s.orgRepo, s.permissionRepo, orgID, id, unlockedBy); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.userRepo.Unlock(id); err != nil {
+		return err
+	}
+
+	// Otherwise the login back-off keeps the user out after the lock is gone.
+	if err := s.loginThrottler.ResetAccount(id.String()); err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"unlocked_by":        unlockedBy.String(),
+		"failed_login_count": user.FailedLoginCount,
+	}
+	if user.LockedUntil != nil {
+		payload["locked_until"] = user.LockedUntil.UTC().Format(time.RFC3339)
+	}
+	s.auditService.LogEvent(models.AuditEventAccountUnlocked, &id, payload, ip, userAgent)
+
+	return nil
+}
+
 func (s *UserService) ChangePassword(userID uuid.UUID, oldPassword, newPassword, ip, userAgent string) error {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
@@ -215,6 +615,10 @@ func (s *UserService) ChangePassword(userID uuid.UUID, oldPassword, newPassword,
 		return err
 	}
 
+	if err := s.securityStamps.Rotate(userID); err != nil {
+		return err
+	}
+
 	s.auditService.LogEvent(models.AuditEventPasswordChange, &userID, nil, ip, userAgent)
 
 	return nil
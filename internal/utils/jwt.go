@@ -7,10 +7,15 @@ import (
 	"github.com/google/uuid"
 )
 
+// JWTClaims carries the organization the session is acting in; Roles are
+// the user's roles within that organization only.
 type JWTClaims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
-	Roles  []string  `json:"roles"`
+	UserID         uuid.UUID `json:"user_id"`
+	Email          string    `json:"email"`
+	Roles          []string  `json:"roles"`
+	SessionID      uuid.UUID `json:"sid"`
+	OrganizationID uuid.UUID `json:"org_id"`
+	SecurityStamp  string    `json:"sst,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -26,15 +31,19 @@ func NewJWTManager(signingKey string, expiry time.Duration) *JWTManager {
 	}
 }
 
-func (m *JWTManager) GenerateToken(userID uuid.UUID, email string, roles []string) (string, error) {
+func (m *JWTManager) GenerateToken(userID uuid.UUID, email string, roles []string, sessionID, orgID uuid.UUID, securityStamp string) (string, error) {
 	claims := JWTClaims{
-		UserID: userID,
-		Email:  email,
-		Roles:  roles,
+		UserID:         userID,
+		Email:          email,
+		Roles:          roles,
+		SessionID:      sessionID,
+		OrganizationID: orgID,
+		SecurityStamp:  securityStamp,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Subject:   userID.String(),
+			ID:        uuid.New().String(),
 		},
 	}
 
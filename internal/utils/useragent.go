@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"net"
+
+	"github.com/mssola/useragent"
+)
+
+type UserAgentInfo struct {
+	Browser        string
+	BrowserVersion string
+	OS             string
+	Platform       string
+	Mobile         bool
+	Bot            bool
+}
+
+func ParseUserAgent(raw string) UserAgentInfo {
+	ua := useragent.New(raw)
+	browser, version := ua.Browser()
+
+	return UserAgentInfo{
+		Browser:        browser,
+		BrowserVersion: version,
+		OS:             ua.OS(),
+		Platform:       ua.Platform(),
+		Mobile:         ua.Mobile(),
+		Bot:            ua.Bot(),
+	}
+}
+
+// DeviceFingerprint identifies a device by browser family, OS and network
+// prefix rather than the exact user agent and address, so browser updates
+// and DHCP churn don't look like a new device.
+func DeviceFingerprint(userAgent, ip string) (fingerprint string, info UserAgentInfo, prefix string) {
+	info = ParseUserAgent(userAgent)
+	prefix = IPPrefix(ip)
+	fingerprint = HashToken(info.Browser + "|" + info.OS + "|" + prefix)
+	return fingerprint, info, prefix
+}
+
+// IPPrefix returns the /24 network of an IPv4 address or the /48 of an
+// IPv6 address.
+func IPPrefix(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: parsed.Mask(net.CIDRMask(48, 128)), Mask: net.CIDRMask(48, 128)}).String()
+}
@@ -6,6 +6,8 @@ import (
 )
 
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+var usernameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{2,31}$`)
+var phoneRegex = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
 
 func ValidateEmail(email string) bool {
 	return emailRegex.MatchString(email)
@@ -30,3 +32,26 @@ func ValidatePassword(password string) (bool, string) {
 func SanitizeEmail(email string) string {
 	return strings.ToLower(strings.TrimSpace(email))
 }
+
+func ValidateUsername(username string) bool {
+	return usernameRegex.MatchString(username)
+}
+
+func SanitizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// ValidatePhone checks for an E.164 number such as +14155550123.
+func ValidatePhone(phone string) bool {
+	return phoneRegex.MatchString(phone)
+}
+
+func SanitizePhone(phone string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '(', ')', '.':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(phone))
+}
@@ -0,0 +1,7 @@
+-- Self-service account deletion (GDPR erasure)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_for TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_users_deletion_scheduled_for ON users(deletion_scheduled_for)
+    WHERE deletion_scheduled_for IS NOT NULL AND erased_at IS NULL;
@@ -0,0 +1,17 @@
+-- Invitations for admin-created users
+CREATE TABLE IF NOT EXISTS user_invitations (
+    id UUID PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    display_name VARCHAR(255),
+    role_ids INTEGER[] NOT NULL DEFAULT '{}',
+    token_hash VARCHAR(255) NOT NULL,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_user_invitations_token_hash ON user_invitations(token_hash);
+CREATE INDEX idx_user_invitations_email ON user_invitations(email);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_last_activity;
+ALTER TABLE users DROP COLUMN IF EXISTS dormancy_warned_at;
@@ -0,0 +1,4 @@
+-- Dormant account warnings
+ALTER TABLE users ADD COLUMN IF NOT EXISTS dormancy_warned_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_users_last_activity ON users(COALESCE(last_login_at, created_at)) WHERE is_active = true;
@@ -0,0 +1,4 @@
+-- Custom user attributes, split into user-editable and admin-only sections
+ALTER TABLE users ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{"user": {}, "admin": {}}'::jsonb;
+
+CREATE INDEX idx_users_metadata ON users USING GIN (metadata);
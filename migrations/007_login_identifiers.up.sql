@@ -0,0 +1,6 @@
+-- Optional username and phone login identifiers
+ALTER TABLE users ADD COLUMN IF NOT EXISTS username VARCHAR(50);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone VARCHAR(20);
+
+CREATE UNIQUE INDEX idx_users_username ON users(username) WHERE username IS NOT NULL;
+CREATE UNIQUE INDEX idx_users_phone ON users(phone) WHERE phone IS NOT NULL;
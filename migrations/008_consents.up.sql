@@ -0,0 +1,24 @@
+-- Versioned terms of service / privacy policy documents
+CREATE TABLE IF NOT EXISTS consent_documents (
+    id SERIAL PRIMARY KEY,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('terms', 'privacy')),
+    version VARCHAR(50) NOT NULL,
+    url TEXT,
+    published_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    published_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    UNIQUE (type, version)
+);
+
+CREATE INDEX idx_consent_documents_type_published ON consent_documents(type, published_at DESC);
+
+-- Per-user acceptance records
+CREATE TABLE IF NOT EXISTS user_consents (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    document_id INTEGER NOT NULL REFERENCES consent_documents(id) ON DELETE CASCADE,
+    accepted_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    PRIMARY KEY (user_id, document_id)
+);
+
+CREATE INDEX idx_user_consents_document_id ON user_consents(document_id);
@@ -0,0 +1,11 @@
+-- Stable session identifiers that survive refresh token rotation
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_id UUID;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_started_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE refresh_tokens SET session_id = id, session_started_at = issued_at WHERE session_id IS NULL;
+
+ALTER TABLE refresh_tokens ALTER COLUMN session_id SET NOT NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN session_started_at SET NOT NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN session_started_at SET DEFAULT NOW();
+
+CREATE INDEX idx_refresh_tokens_session_id ON refresh_tokens(user_id, session_id);
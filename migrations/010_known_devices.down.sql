@@ -0,0 +1,5 @@
+DELETE FROM email_tokens WHERE type = 'not_me';
+ALTER TABLE email_tokens DROP CONSTRAINT IF EXISTS email_tokens_type_check;
+ALTER TABLE email_tokens ADD CONSTRAINT email_tokens_type_check CHECK (type IN ('verify', 'reset'));
+ALTER TABLE email_tokens DROP COLUMN IF EXISTS session_id;
+DROP TABLE IF EXISTS known_devices;
@@ -0,0 +1,20 @@
+-- Devices a user has signed in from, used for new-device notifications
+CREATE TABLE IF NOT EXISTS known_devices (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    fingerprint VARCHAR(64) NOT NULL,
+    browser VARCHAR(100),
+    os VARCHAR(100),
+    ip_prefix VARCHAR(64),
+    first_session_id UUID,
+    first_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, fingerprint)
+);
+
+CREATE INDEX idx_known_devices_user_id ON known_devices(user_id);
+
+-- "This wasn't me" links point at the session they were sent for
+ALTER TABLE email_tokens ADD COLUMN IF NOT EXISTS session_id UUID;
+ALTER TABLE email_tokens DROP CONSTRAINT IF EXISTS email_tokens_type_check;
+ALTER TABLE email_tokens ADD CONSTRAINT email_tokens_type_check CHECK (type IN ('verify', 'reset', 'not_me'));
@@ -0,0 +1,2 @@
+-- Per-role override of the concurrent session limit (NULL = use the global default, 0 = unlimited)
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS max_sessions INTEGER CHECK (max_sessions >= 0);
@@ -0,0 +1,19 @@
+DELETE FROM email_tokens WHERE type = 'step_up';
+ALTER TABLE email_tokens DROP CONSTRAINT IF EXISTS email_tokens_type_check;
+ALTER TABLE email_tokens ADD CONSTRAINT email_tokens_type_check CHECK (type IN ('verify', 'reset', 'not_me'));
+
+DROP INDEX IF EXISTS idx_audit_events_user_event;
+
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS geo_as_org;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS geo_asn;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS geo_longitude;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS geo_latitude;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS geo_city;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS geo_country;
+
+ALTER TABLE audit_events DROP COLUMN IF EXISTS geo_as_org;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS geo_asn;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS geo_longitude;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS geo_latitude;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS geo_city;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS geo_country;
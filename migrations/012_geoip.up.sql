@@ -0,0 +1,20 @@
+-- GeoIP enrichment for audit events and sessions
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS geo_country VARCHAR(2);
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS geo_city VARCHAR(255);
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS geo_latitude DOUBLE PRECISION;
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS geo_longitude DOUBLE PRECISION;
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS geo_asn INTEGER;
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS geo_as_org VARCHAR(255);
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS geo_country VARCHAR(2);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS geo_city VARCHAR(255);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS geo_latitude DOUBLE PRECISION;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS geo_longitude DOUBLE PRECISION;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS geo_asn INTEGER;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS geo_as_org VARCHAR(255);
+
+CREATE INDEX idx_audit_events_user_event ON audit_events(user_id, event_type, created_at DESC);
+
+-- Emailed step-up codes for logins from implausible locations
+ALTER TABLE email_tokens DROP CONSTRAINT IF EXISTS email_tokens_type_check;
+ALTER TABLE email_tokens ADD CONSTRAINT email_tokens_type_check CHECK (type IN ('verify', 'reset', 'not_me', 'step_up'));
@@ -0,0 +1,37 @@
+-- Fine-grained permissions granted to roles
+CREATE TABLE IF NOT EXISTS permissions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id INTEGER NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE INDEX idx_role_permissions_permission_id ON role_permissions(permission_id);
+
+-- Default permissions
+INSERT INTO permissions (name, description) VALUES
+    ('users:read', 'View users and their sessions'),
+    ('users:write', 'Create, update, delete, suspend and unlock users and revoke their sessions'),
+    ('users:invite', 'Invite new users and manage pending invitations'),
+    ('roles:write', 'Create, update and delete roles'),
+    ('roles:assign', 'Assign and unassign roles to users'),
+    ('permissions:manage', 'Create permissions and grant them to roles'),
+    ('consents:manage', 'Publish terms of service and privacy policy versions'),
+    ('audit:read', 'Read audit logs and consent reports')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+INNER JOIN permissions p ON p.name IN ('users:read', 'audit:read')
+WHERE r.name = 'auditor'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+-- Role hierarchy: a role implicitly grants every role it inherits
+CREATE TABLE IF NOT EXISTS role_inheritance (
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    inherits_role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, inherits_role_id),
+    CHECK (role_id <> inherits_role_id)
+);
+
+CREATE INDEX idx_role_inheritance_inherits ON role_inheritance(inherits_role_id);
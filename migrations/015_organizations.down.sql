@@ -0,0 +1,13 @@
+DELETE FROM permissions WHERE name = 'organizations:create';
+
+ALTER TABLE user_invitations DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS organization_id;
+
+-- Grants outside the default organization cannot be represented without the column
+DELETE FROM user_roles WHERE organization_id <> '00000000-0000-0000-0000-000000000001';
+ALTER TABLE user_roles DROP CONSTRAINT IF EXISTS user_roles_pkey;
+ALTER TABLE user_roles DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE user_roles ADD PRIMARY KEY (user_id, role_id);
+
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
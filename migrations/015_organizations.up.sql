@@ -0,0 +1,50 @@
+-- Organizations (tenants). Roles stay global definitions but are granted per organization.
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY,
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+INSERT INTO organizations (id, slug, name)
+VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Default Organization')
+ON CONFLICT (id) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    joined_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX idx_organization_members_user_id ON organization_members(user_id);
+
+INSERT INTO organization_members (organization_id, user_id, joined_at)
+SELECT '00000000-0000-0000-0000-000000000001', id, created_at FROM users
+ON CONFLICT DO NOTHING;
+
+-- Existing role grants move into the default organization
+ALTER TABLE user_roles ADD COLUMN IF NOT EXISTS organization_id UUID NOT NULL
+    DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES organizations(id) ON DELETE CASCADE;
+ALTER TABLE user_roles ALTER COLUMN organization_id DROP DEFAULT;
+ALTER TABLE user_roles DROP CONSTRAINT IF EXISTS user_roles_pkey;
+ALTER TABLE user_roles ADD PRIMARY KEY (user_id, organization_id, role_id);
+
+-- Each session is bound to the organization it is currently acting in
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS organization_id UUID
+    REFERENCES organizations(id) ON DELETE CASCADE;
+UPDATE refresh_tokens SET organization_id = '00000000-0000-0000-0000-000000000001' WHERE organization_id IS NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN organization_id SET NOT NULL;
+
+ALTER TABLE user_invitations ADD COLUMN IF NOT EXISTS organization_id UUID NOT NULL
+    DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES organizations(id) ON DELETE CASCADE;
+ALTER TABLE user_invitations ALTER COLUMN organization_id DROP DEFAULT;
+
+INSERT INTO permissions (name, description) VALUES
+    ('organizations:create', 'Create new organizations')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name = 'organizations:create'
+ON CONFLICT DO NOTHING;
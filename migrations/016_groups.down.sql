@@ -0,0 +1,5 @@
+DELETE FROM permissions WHERE name = 'groups:manage';
+
+DROP TABLE IF EXISTS group_roles;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_roles_expires_at;
+ALTER TABLE user_roles DROP CONSTRAINT IF EXISTS user_roles_validity_check;
+ALTER TABLE user_roles DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE user_roles DROP COLUMN IF EXISTS starts_at;
@@ -0,0 +1,8 @@
+-- Time-bound role assignments: a grant only applies between starts_at and expires_at
+ALTER TABLE user_roles ADD COLUMN IF NOT EXISTS starts_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE user_roles ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE user_roles ADD CONSTRAINT user_roles_validity_check
+    CHECK (starts_at IS NULL OR expires_at IS NULL OR expires_at > starts_at);
+
+CREATE INDEX idx_user_roles_expires_at ON user_roles(expires_at) WHERE expires_at IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Lets trusted callers (other services) ask for authorization decisions
+INSERT INTO permissions (name, description) VALUES
+    ('authz:check', 'Ask for authorization decisions on behalf of any user')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name = 'authz:check'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DELETE FROM permissions WHERE name IN ('relations:read', 'relations:manage');
+
+DROP TABLE IF EXISTS relation_tuples;
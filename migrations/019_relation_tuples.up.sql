@@ -0,0 +1,25 @@
+-- Relationship tuples: "subject has relation to namespace:object_id", scoped to an organization
+CREATE TABLE IF NOT EXISTS relation_tuples (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    namespace VARCHAR(64) NOT NULL,
+    object_id VARCHAR(255) NOT NULL,
+    relation VARCHAR(64) NOT NULL,
+    subject_namespace VARCHAR(64) NOT NULL,
+    subject_id VARCHAR(255) NOT NULL,
+    subject_relation VARCHAR(64) NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (organization_id, namespace, object_id, relation, subject_namespace, subject_id, subject_relation)
+);
+
+CREATE INDEX idx_relation_tuples_subject ON relation_tuples(organization_id, subject_namespace, subject_id);
+
+INSERT INTO permissions (name, description) VALUES
+    ('relations:read', 'Read relationship tuples and check, expand or list relations for any user'),
+    ('relations:manage', 'Write and delete relationship tuples')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name IN ('relations:read', 'relations:manage')
+ON CONFLICT DO NOTHING;
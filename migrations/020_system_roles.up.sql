@@ -0,0 +1,4 @@
+-- System roles are referenced by name in code and cannot be renamed or deleted
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS is_system BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE roles SET is_system = true WHERE name IN ('admin', 'user', 'auditor');
@@ -0,0 +1,3 @@
+-- Security stamp: changes whenever issued access tokens must stop being trusted
+ALTER TABLE users ADD COLUMN IF NOT EXISTS security_stamp VARCHAR(64) NOT NULL
+    DEFAULT md5(random()::text || clock_timestamp()::text);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS elevation_requests;
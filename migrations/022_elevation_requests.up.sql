@@ -0,0 +1,20 @@
+-- Just-in-time elevation: a user asks for a role for a limited time and an approver grants it
+CREATE TABLE IF NOT EXISTS elevation_requests (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    duration_minutes INTEGER NOT NULL CHECK (duration_minutes > 0),
+    justification TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    decided_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    decision_note TEXT NOT NULL DEFAULT '',
+    approved_at TIMESTAMP WITH TIME ZONE,
+    denied_at TIMESTAMP WITH TIME ZONE,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    grant_expires_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_elevation_requests_organization_id ON elevation_requests(organization_id, created_at);
+CREATE INDEX idx_elevation_requests_user_id ON elevation_requests(user_id);
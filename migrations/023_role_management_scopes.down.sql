@@ -0,0 +1,3 @@
+DELETE FROM permissions WHERE name = 'roles:delegate';
+
+DROP TABLE IF EXISTS role_management_scopes;
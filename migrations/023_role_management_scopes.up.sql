@@ -0,0 +1,20 @@
+-- Delegated administration: holders of manager_role_id may assign and unassign managed_role_id
+CREATE TABLE IF NOT EXISTS role_management_scopes (
+    manager_role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    managed_role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    PRIMARY KEY (manager_role_id, managed_role_id)
+);
+
+CREATE INDEX idx_role_management_scopes_managed ON role_management_scopes(managed_role_id);
+
+INSERT INTO permissions (name, description) VALUES
+    ('roles:delegate', 'Assign and unassign the roles covered by the caller''s role-management scopes')
+ON CONFLICT (name) DO NOTHING;
+
+-- Whoever could assign roles so far keeps reaching the role assignment endpoints
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT rp.role_id, p.id FROM role_permissions rp
+INNER JOIN permissions existing ON rp.permission_id = existing.id AND existing.name = 'roles:assign'
+CROSS JOIN permissions p
+WHERE p.name = 'roles:delegate'
+ON CONFLICT DO NOTHING;